@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/service"
+	"tender-service/utils"
+)
+
+type AuthHandler struct {
+	authService service.AuthService
+	logger      *slog.Logger
+}
+
+func NewAuthHandler(authService service.AuthService, logger *slog.Logger) *AuthHandler {
+	return &AuthHandler{authService: authService, logger: logger}
+}
+
+func (h *AuthHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.Username == "" || request.Password == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "username and password are required")
+		return
+	}
+
+	token, expiresAt, err := h.authService.IssueToken(r.Context(), request.Username, request.Password)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrInvalidCredentials) {
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "Invalid credentials")
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "Error issuing token", "username", request.Username, "error", err)
+		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"token":     token,
+		"tokenType": "Bearer",
+		"expiresAt": expiresAt.Format(time.RFC3339),
+	})
+}
@@ -3,10 +3,12 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"tender-service/internal/auth"
 	"tender-service/internal/models"
+	"tender-service/internal/repository"
 	"tender-service/internal/service"
 	"tender-service/utils"
 
@@ -18,10 +20,11 @@ import (
 
 type BidHandler struct {
 	bidService service.BidService
+	logger     *slog.Logger
 }
 
-func NewBidHandler(bidService service.BidService) *BidHandler {
-	return &BidHandler{bidService: bidService}
+func NewBidHandler(bidService service.BidService, logger *slog.Logger) *BidHandler {
+	return &BidHandler{bidService: bidService, logger: logger}
 }
 
 func (h *BidHandler) CreateBid(w http.ResponseWriter, r *http.Request) {
@@ -38,11 +41,12 @@ func (h *BidHandler) CreateBid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx := auth.WithLegacyUserID(r.Context(), request.UserID)
 	createdBid, err := h.bidService.CreateBid(
+		ctx,
 		request.Description,
 		request.TenderID,
 		request.OrganizationID,
-		request.UserID,
 		models.BidAuthorType(request.AuthorType),
 	)
 	if err != nil {
@@ -51,10 +55,14 @@ func (h *BidHandler) CreateBid(w http.ResponseWriter, r *http.Request) {
 			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid UUID format")
 		case errors.Is(err, my_errors.ErrTenderNotFound):
 			utils.WriteErrorResponse(w, http.StatusNotFound, "Tender not found")
-		case errors.Is(err, my_errors.ErrUserNotFound):
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrSubmissionClosed):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Tender submission deadline has passed")
+		case errors.Is(err, my_errors.ErrTenderNotPublished):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Tender is not accepting bids")
 		case errors.Is(err, my_errors.ErrBadRequest):
 			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request")
 		default:
@@ -63,46 +71,59 @@ func (h *BidHandler) CreateBid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setETag(w, createdBid.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(createdBid)
 }
 
 func (h *BidHandler) GetUserBids(w http.ResponseWriter, r *http.Request) {
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+	options, err := queryOptions(r, 10)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
 		return
 	}
 
-	limitParam := r.URL.Query().Get("limit")
-	offsetParam := r.URL.Query().Get("offset")
-
-	limit, err := strconv.Atoi(limitParam)
-	if err != nil || limit <= 0 {
-		limit = 10
-	}
-	offset, err := strconv.Atoi(offsetParam)
-	if err != nil || offset < 0 {
-		offset = 0
+	bids, err := h.bidService.GetUserBids(r.Context(), options)
+	if err != nil {
+		switch {
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, my_errors.ErrBadRequest):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+		default:
+			h.logger.ErrorContext(r.Context(), "Error fetching user bids", "error", err)
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error retrieving bids")
+		}
+		return
 	}
 
-	log.Printf("GetUserBids called for username: %s, limit: %d, offset: %d", username, limit, offset)
+	writeList(w, r, h.logger, bids, options, repository.BidCursor)
+}
+
+// SearchBids serves full-text search over the bids the caller may view.
+// Results are ranked, so cursor paging is not available.
+func (h *BidHandler) SearchBids(w http.ResponseWriter, r *http.Request) {
+	options, err := queryOptions(r, 10)
+	if err != nil || cursorMode(r) {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+		return
+	}
 
-	bids, err := h.bidService.GetUserBids(username, limit, offset)
+	results, err := h.bidService.SearchBids(r.Context(), r.URL.Query().Get("q"), options)
 	if err != nil {
 		switch {
-		case errors.Is(err, my_errors.ErrUserNotFound):
-			log.Printf("Error retrieving user by username: %s, error: %v", username, err)
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, my_errors.ErrBadRequest):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
 		default:
-			log.Printf("Error retrieving bids for username: %s, error: %v", username, err)
-			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error retrieving bids")
+			h.logger.ErrorContext(r.Context(), "Error searching bids", "error", err)
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error searching bids")
 		}
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(bids)
+	writeJSON(w, r, h.logger, results)
 }
 
 func (h *BidHandler) GetBidsByTenderID(w http.ResponseWriter, r *http.Request) {
@@ -114,28 +135,18 @@ func (h *BidHandler) GetBidsByTenderID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	username := r.URL.Query().Get("username")
-	limitParam := r.URL.Query().Get("limit")
-	offsetParam := r.URL.Query().Get("offset")
-
-	limit, err := strconv.Atoi(limitParam)
-	if err != nil || limit <= 0 {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
-		return
-	}
-
-	offset, err := strconv.Atoi(offsetParam)
-	if err != nil || offset < 0 {
+	options, err := queryOptions(r, 10)
+	if err != nil {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
 		return
 	}
 
-	bids, err := h.bidService.GetBidsByTenderID(tenderID, username, limit, offset)
+	bids, err := h.bidService.GetBidsByTenderID(r.Context(), tenderID, options)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrTenderNotFound):
 			utils.WriteErrorResponse(w, http.StatusNotFound, "Tender not found")
-		case errors.Is(err, my_errors.ErrUserNotFound):
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
@@ -147,47 +158,36 @@ func (h *BidHandler) GetBidsByTenderID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(bids)
+	writeList(w, r, h.logger, bids, options, repository.BidCursor)
 }
 
 func (h *BidHandler) GetBidStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bidID := vars["bidId"]
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
-		return
-	}
-
-	log.Printf("GetBidStatus: Received request for bidID=%s, username=%s", bidID, username)
-
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		log.Printf("GetBidStatus: Invalid bidID format: %s", bidID)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
 		return
 	}
 
-	status, err := h.bidService.GetBidStatus(bidID, username)
+	status, version, err := h.bidService.GetBidStatus(r.Context(), bidID)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrBidNotFound):
 			utils.WriteErrorResponse(w, http.StatusNotFound, "Bid not found")
-		case errors.Is(err, my_errors.ErrUserNotFound):
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
 		default:
-			log.Printf("GetBidStatus: Internal server error for bidID=%s, username=%s: %v", bidID, username, err)
+			h.logger.ErrorContext(r.Context(), "Error fetching bid status", "bid_id", bidID, "error", err)
 			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
 		}
 		return
 	}
 
-	log.Printf("GetBidStatus: Successfully returned status for bidID=%s, username=%s", bidID, username)
-
+	setETag(w, version)
 	w.Header().Set("Content-Type", "text/plain")
 	w.Write([]byte(status))
 }
@@ -197,142 +197,295 @@ func (h *BidHandler) UpdateBidStatus(w http.ResponseWriter, r *http.Request) {
 	bidID := vars["bidId"]
 
 	status := r.URL.Query().Get("status")
-	username := r.URL.Query().Get("username")
-	if status == "" || username == "" {
-		http.Error(w, "status and username are required", http.StatusBadRequest)
+	if status == "" {
+		http.Error(w, "status is required", http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("UpdateBidStatus: Received request for bidID=%s, status=%s, username=%s", bidID, status, username)
-
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		log.Printf("UpdateBidStatus: Invalid bidID format: %s", bidID)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
 		return
 	}
 
-	err = h.bidService.UpdateBidStatus(bidID, status, username)
+	expected, err := expectedVersion(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid expected version")
+		return
+	}
+
+	bid, err := h.bidService.UpdateBidStatus(r.Context(), bidID, status, expected)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrBidNotFound):
 			utils.WriteErrorResponse(w, http.StatusNotFound, "Bid not found")
-		case errors.Is(err, my_errors.ErrUserNotFound):
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrInvalidBidStatus):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid status")
+		case errors.Is(err, my_errors.ErrInvalidBidTransition):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Bid status transition not allowed")
+		case errors.Is(err, my_errors.ErrVersionConflict):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Version conflict")
 		default:
-			log.Printf("UpdateBidStatus: Internal server error for bidID=%s, status=%s, username=%s: %v", bidID, status, username, err)
+			h.logger.ErrorContext(r.Context(), "Error updating bid status", "bid_id", bidID, "status", status, "error", err)
 			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
 		}
 		return
 	}
 
-	log.Printf("UpdateBidStatus: Successfully updated status for bidID=%s, username=%s", bidID, username)
-	w.WriteHeader(http.StatusOK)
+	setETag(w, bid.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bidResponse(bid))
 }
 
 func (h *BidHandler) EditBid(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bidID := vars["bidId"]
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		http.Error(w, "username is required", http.StatusBadRequest)
-		return
-	}
-
-	log.Printf("EditBid: Received request for bidID=%s, username=%s", bidID, username)
-
 	var payload map[string]interface{}
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
-		log.Printf("EditBid: Error decoding request body: %v", err)
+		h.logger.DebugContext(r.Context(), "Error decoding request body", "error", err)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	err := h.bidService.EditBid(bidID, username, payload)
+	expected, err := expectedVersion(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid expected version")
+		return
+	}
+
+	bid, err := h.bidService.EditBid(r.Context(), bidID, expected, payload)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrBidNotFound):
 			utils.WriteErrorResponse(w, http.StatusNotFound, "Bid not found")
-		case errors.Is(err, my_errors.ErrUserNotFound):
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrBidNotEditable):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Bid cannot be edited in its current status")
+		case errors.Is(err, my_errors.ErrVersionConflict):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Version conflict")
 		case errors.Is(err, my_errors.ErrBadRequest):
 			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
 		default:
-			log.Printf("EditBid: Internal server error for bidID=%s, username=%s: %v", bidID, username, err)
+			h.logger.ErrorContext(r.Context(), "Error editing bid", "bid_id", bidID, "error", err)
 			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
 		}
 		return
 	}
 
-	log.Printf("EditBid: Successfully edited bid for bidID=%s, username=%s", bidID, username)
-	w.WriteHeader(http.StatusOK)
+	setETag(w, bid.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bidResponse(bid))
 }
 
 func (h *BidHandler) SubmitBidFeedback(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	bidID := vars["bidId"]
 
-	username := r.URL.Query().Get("username")
 	bidFeedback := r.URL.Query().Get("bidFeedback")
 
-	if username == "" {
-		log.Println("SubmitBidFeedback: Username is missing")
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Username is required")
+	if bidFeedback == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Feedback is required")
 		return
 	}
 
-	if bidFeedback == "" {
-		log.Println("SubmitBidFeedback: Feedback is missing")
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Feedback is required")
+	_, err := uuid.Parse(bidID)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
+		return
+	}
+
+	bid, err := h.bidService.SubmitBidFeedback(r.Context(), bidID, bidFeedback)
+	if err != nil {
+		switch {
+		case errors.Is(err, my_errors.ErrBidNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Bid not found")
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, my_errors.ErrForbidden):
+			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		default:
+			h.logger.ErrorContext(r.Context(), "Error submitting bid feedback", "bid_id", bidID, "error", err)
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		}
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(bidResponse(bid))
+}
+
+func (h *BidHandler) SubmitBidDecision(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bidID := vars["bidId"]
+
+	decisionParam := r.URL.Query().Get("decision")
+
+	if decisionParam == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "decision is required")
+		return
+	}
+
+	decision, err := models.ParseBidDecision(decisionParam)
+	if err != nil {
+		h.logger.DebugContext(r.Context(), "Invalid bid decision", "decision", decisionParam)
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid decision")
+		return
+	}
+
+	_, err = uuid.Parse(bidID)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
+		return
+	}
+
+	bid, err := h.bidService.SubmitBidDecision(r.Context(), bidID, decision)
+	if err != nil {
+		switch {
+		case errors.Is(err, my_errors.ErrBidNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Bid not found")
+		case errors.Is(err, my_errors.ErrTenderNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Tender not found")
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, my_errors.ErrForbidden):
+			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrBidDecisionNotAllowed):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Bid cannot be decided in its current status")
+		case errors.Is(err, my_errors.ErrBidDecisionSubmitted):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Decision already submitted")
+		case errors.Is(err, my_errors.ErrTenderAlreadyDecided):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Tender is closed or already has an approved bid")
+		case errors.Is(err, my_errors.ErrVersionConflict):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Version conflict")
+		case errors.Is(err, my_errors.ErrBadRequest):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
+		default:
+			h.logger.ErrorContext(r.Context(), "Error submitting bid decision", "bid_id", bidID, "error", err)
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	setETag(w, bid.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(bidResponse(bid))
+}
+
+func (h *BidHandler) RollbackBid(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bidID := vars["bidId"]
+	versionStr := vars["version"]
+
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		log.Printf("SubmitBidFeedback: Invalid bidID format: %s", bidID)
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
 		return
 	}
 
-	log.Printf("SubmitBidFeedback: Received request for bidID=%s, username=%s, feedback=%s", bidID, username, bidFeedback)
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version <= 0 {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid version number")
+		return
+	}
+
+	expected, err := expectedVersion(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid expected version")
+		return
+	}
 
-	bid, err := h.bidService.SubmitBidFeedback(bidID, username, bidFeedback)
+	bid, err := h.bidService.RollbackBid(r.Context(), bidID, version, expected)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrBidNotFound):
-			log.Printf("SubmitBidFeedback: Bid not found for bidID=%s", bidID)
 			utils.WriteErrorResponse(w, http.StatusNotFound, "Bid not found")
-		case errors.Is(err, my_errors.ErrUserNotFound):
-			log.Printf("SubmitBidFeedback: User not found for username=%s", username)
+		case errors.Is(err, my_errors.ErrBidHistoryNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Bid version not found")
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
-			log.Printf("SubmitBidFeedback: Insufficient permissions for username=%s on bidID=%s", username, bidID)
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrBidNotEditable):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Bid cannot be edited in its current status")
+		case errors.Is(err, my_errors.ErrVersionConflict):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Version conflict")
+		case errors.Is(err, my_errors.ErrBadRequest):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID or version")
+		default:
+			h.logger.ErrorContext(r.Context(), "Error rolling back bid", "bid_id", bidID, "error", err)
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
+		}
+		return
+	}
+
+	setETag(w, bid.Version)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(bidResponse(bid))
+}
+
+func (h *BidHandler) GetBidReviews(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tenderID := vars["tenderId"]
+
+	authorUsername := r.URL.Query().Get("authorUsername")
+	if authorUsername == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "authorUsername is required")
+		return
+	}
+
+	if _, err := uuid.Parse(tenderID); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid tender ID format")
+		return
+	}
+
+	options, err := queryOptions(r, 5)
+	if err != nil || cursorMode(r) {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+		return
+	}
+
+	reviews, err := h.bidService.GetBidReviews(r.Context(), tenderID, authorUsername, options)
+	if err != nil {
+		switch {
+		case errors.Is(err, my_errors.ErrTenderNotFound):
+			utils.WriteErrorResponse(w, http.StatusNotFound, "Tender not found")
+		case errors.Is(err, my_errors.ErrUserNotFound), errors.Is(err, my_errors.ErrUnauthorized):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, my_errors.ErrForbidden):
+			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrBadRequest):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
 		default:
-			log.Printf("SubmitBidFeedback: Internal server error for bidID=%s, username=%s: %v", bidID, username, err)
+			h.logger.ErrorContext(r.Context(), "Error fetching bid reviews", "tender_id", tenderID, "error", err)
 			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Internal server error")
 		}
 		return
 	}
 
-	log.Printf("SubmitBidFeedback: Successfully submitted feedback for bidID=%s, username=%s", bidID, username)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reviews)
+}
 
-	response := map[string]interface{}{
+func bidResponse(bid *models.Bid) map[string]interface{} {
+	return map[string]interface{}{
 		"id":         bid.ID,
 		"name":       bid.Description,
 		"status":     bid.Status,
+		"tenderId":   bid.TenderID,
 		"authorType": bid.AuthorType,
 		"authorId":   bid.UserID,
-		// "version":    bid.Version,
-		"createdAt": bid.CreatedAt,
+		"version":    bid.Version,
+		"createdAt":  bid.CreatedAt,
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
 }
@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	my_errors "tender-service/internal/errors"
+	"tender-service/internal/logging"
 	"tender-service/internal/models"
+	"tender-service/internal/repository"
 	"testing"
 
 	"github.com/google/uuid"
@@ -16,7 +19,8 @@ import (
 
 type MockBidService struct{}
 
-func (m *MockBidService) GetBidsByTenderID(tenderID, username string, limit, offset int) ([]models.Bid, error) {
+func (m *MockBidService) GetBidsByTenderID(ctx context.Context, tenderID string, options repository.QueryOptions) ([]models.Bid, error) {
+	username := mockCaller(ctx)
 	if tenderID == "invalid-uuid-format" {
 		return nil, my_errors.ErrBadRequest
 	}
@@ -36,7 +40,8 @@ func (m *MockBidService) GetBidsByTenderID(tenderID, username string, limit, off
 	}, nil
 }
 
-func (m *MockBidService) CreateBid(description, tenderID, organizationID, userID string, authorType models.BidAuthorType) (*models.Bid, error) {
+func (m *MockBidService) CreateBid(ctx context.Context, description, tenderID, organizationID string, authorType models.BidAuthorType) (*models.Bid, error) {
+	userID := mockCaller(ctx)
 	if tenderID == "invalid-uuid-format" || tenderID == "non-existent-tender-id" || userID == "non-existent-user-id" {
 		return nil, my_errors.ErrBadRequest
 	}
@@ -55,7 +60,15 @@ func (m *MockBidService) CreateBid(description, tenderID, organizationID, userID
 	}, nil
 }
 
-func (m *MockBidService) GetUserBids(userID string, limit, offset int) ([]models.Bid, error) {
+func (m *MockBidService) SearchBids(ctx context.Context, query string, options repository.QueryOptions) ([]models.BidSearchResult, error) {
+	if mockCaller(ctx) == "non-existent-user-id" {
+		return nil, my_errors.ErrUserNotFound
+	}
+	return []models.BidSearchResult{}, nil
+}
+
+func (m *MockBidService) GetUserBids(ctx context.Context, options repository.QueryOptions) ([]models.Bid, error) {
+	userID := mockCaller(ctx)
 	if userID == "non-existent-user-id" {
 		return nil, my_errors.ErrUserNotFound
 	}
@@ -72,61 +85,95 @@ func (m *MockBidService) GetUserBids(userID string, limit, offset int) ([]models
 	}, nil
 }
 
-func (m *MockBidService) GetBidStatus(bidID, username string) (models.BidStatus, error) {
+func (m *MockBidService) GetBidStatus(ctx context.Context, bidID string) (models.BidStatus, int, error) {
+	username := mockCaller(ctx)
 	if bidID == "invalid-uuid-format" {
-		return "", my_errors.ErrBadRequest
+		return "", 0, my_errors.ErrBadRequest
 	}
 
 	if bidID == "non-existent-bid-id" {
-		return "", my_errors.ErrBidNotFound
+		return "", 0, my_errors.ErrBidNotFound
 	}
 
 	if username == "unauthorized-user" {
-		return "", my_errors.ErrForbidden
+		return "", 0, my_errors.ErrForbidden
 	}
 
 	if bidID == "550e8400-e29b-41d4-a716-446655440099" && username == "user1" {
-		return models.BidStatusCreated, nil
+		return models.BidStatusCreated, mockBidVersion, nil
 	}
 
-	return "", my_errors.ErrBidNotFound
+	return "", 0, my_errors.ErrBidNotFound
 }
 
-func (m *MockBidService) UpdateBidStatus(bidID, status, username string) error {
+// mockBidVersion is the version every bid served by MockBidService is
+// currently at.
+const mockBidVersion = 1
+
+func (m *MockBidService) UpdateBidStatus(ctx context.Context, bidID, status string, expectedVersion int) (*models.Bid, error) {
+	username := mockCaller(ctx)
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		return my_errors.ErrBadRequest
+		return nil, my_errors.ErrBadRequest
 	}
 
 	if bidID == "non-existent-bid-id" {
-		return my_errors.ErrBidNotFound
+		return nil, my_errors.ErrBidNotFound
 	}
 
 	if username == "unauthorized-user" {
-		return my_errors.ErrForbidden
+		return nil, my_errors.ErrForbidden
 	}
 
-	return nil
+	if status == "APPROVED" {
+		return nil, my_errors.ErrInvalidBidTransition
+	}
+
+	if expectedVersion != 0 && expectedVersion != mockBidVersion {
+		return nil, my_errors.ErrVersionConflict
+	}
+
+	bid := &models.Bid{
+		ID:      bidID,
+		Status:  models.BidStatus(status),
+		Version: mockBidVersion + 1,
+	}
+	return bid, nil
 }
 
-func (m *MockBidService) EditBid(bidID, username string, updatedFields map[string]interface{}) error {
+func (m *MockBidService) EditBid(ctx context.Context, bidID string, expectedVersion int, updatedFields map[string]interface{}) (*models.Bid, error) {
+	username := mockCaller(ctx)
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		return my_errors.ErrBadRequest
+		return nil, my_errors.ErrBadRequest
 	}
 
 	if bidID == "non-existent-bid-id" {
-		return my_errors.ErrBidNotFound
+		return nil, my_errors.ErrBidNotFound
 	}
 
 	if username == "unauthorized-user" {
-		return my_errors.ErrForbidden
+		return nil, my_errors.ErrForbidden
+	}
+
+	if bidID == "550e8400-e29b-41d4-a716-446655440066" {
+		return nil, my_errors.ErrBidNotEditable
 	}
 
-	return nil
+	if expectedVersion != 0 && expectedVersion != mockBidVersion {
+		return nil, my_errors.ErrVersionConflict
+	}
+
+	bid := &models.Bid{
+		ID:      bidID,
+		Status:  models.BidStatusCreated,
+		Version: mockBidVersion + 1,
+	}
+	return bid, nil
 }
 
-func (m *MockBidService) SubmitBidFeedback(bidID, username, feedback string) (*models.Bid, error) {
+func (m *MockBidService) SubmitBidFeedback(ctx context.Context, bidID, feedback string) (*models.Bid, error) {
+	username := mockCaller(ctx)
 
 	if bidID == "invalid-bid-id" {
 		return nil, my_errors.ErrBadRequest
@@ -152,9 +199,79 @@ func (m *MockBidService) SubmitBidFeedback(bidID, username, feedback string) (*m
 	return bid, nil
 }
 
+func (m *MockBidService) SubmitBidDecision(ctx context.Context, bidID string, decision models.BidDecision) (*models.Bid, error) {
+	username := mockCaller(ctx)
+	if username == "unauthorized-user" {
+		return nil, my_errors.ErrForbidden
+	}
+
+	if username == "second-approver" {
+		return nil, my_errors.ErrBidDecisionSubmitted
+	}
+
+	if username == "concurrent-approver" {
+		return nil, my_errors.ErrVersionConflict
+	}
+
+	status := models.BidStatusPublished
+	if decision == models.BidDecisionRejected {
+		status = models.BidStatusRejected
+	}
+
+	bid := &models.Bid{
+		ID:          bidID,
+		Description: "Test Bid with decision",
+		Status:      status,
+		Version:     mockBidVersion,
+	}
+	return bid, nil
+}
+
+func (m *MockBidService) RollbackBid(ctx context.Context, bidID string, version int, expectedVersion int) (*models.Bid, error) {
+	username := mockCaller(ctx)
+	if version == 999 {
+		return nil, my_errors.ErrBidHistoryNotFound
+	}
+
+	if username == "unauthorized-user" {
+		return nil, my_errors.ErrForbidden
+	}
+
+	bid := &models.Bid{
+		ID:          bidID,
+		Description: "Rolled back bid",
+		Status:      models.BidStatusCreated,
+		Version:     version + 1,
+	}
+	return bid, nil
+}
+
+func (m *MockBidService) GetBidReviews(ctx context.Context, tenderID, authorUsername string, options repository.QueryOptions) ([]models.BidReview, error) {
+	requesterUsername := mockCaller(ctx)
+	if requesterUsername == "" {
+		return nil, my_errors.ErrUnauthorized
+	}
+
+	if requesterUsername == "unauthorized-user" {
+		return nil, my_errors.ErrForbidden
+	}
+
+	if authorUsername == "non-existent-user" {
+		return nil, my_errors.ErrUserNotFound
+	}
+
+	return []models.BidReview{
+		{
+			ID:          "550e8400-e29b-41d4-a716-446655440077",
+			BidID:       "550e8400-e29b-41d4-a716-446655440099",
+			Description: "All good",
+		},
+	}, nil
+}
+
 func TestCreateBid_Success(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	bid := models.Bid{
 		Description:    "Успешное создание bid",
@@ -177,7 +294,7 @@ func TestCreateBid_Success(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
-	handler.CreateBid(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.CreateBid)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	var createdBid models.Bid
@@ -188,7 +305,7 @@ func TestCreateBid_Success(t *testing.T) {
 
 func TestCreateBid_InvalidUUID(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"description":    "Неверный формат tenderId",
@@ -204,14 +321,14 @@ func TestCreateBid_InvalidUUID(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
-	handler.CreateBid(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.CreateBid)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
 func TestCreateBid_TenderNotFound(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"description":    "Тест с несуществующим tenderId",
@@ -227,14 +344,14 @@ func TestCreateBid_TenderNotFound(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
-	handler.CreateBid(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.CreateBid)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
 func TestCreateBid_UserNotFound(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"description":    "Тест с несуществующим userId",
@@ -250,14 +367,14 @@ func TestCreateBid_UserNotFound(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
-	handler.CreateBid(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.CreateBid)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
 func TestCreateBid_Forbidden(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"description":    "Недостаточно прав для выполнения действия",
@@ -273,20 +390,20 @@ func TestCreateBid_Forbidden(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
-	handler.CreateBid(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.CreateBid)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusForbidden, rr.Code)
 }
 
 func TestGetUserBids_Success(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/my?username=user1", nil)
 	assert.NoError(t, err)
 
 	rr := httptest.NewRecorder()
-	handler.GetUserBids(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.GetUserBids)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	var bids []models.Bid
@@ -298,13 +415,13 @@ func TestGetUserBids_Success(t *testing.T) {
 
 func TestGetUserBids_UserNotFound(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/my?username=non-existent-user-id", nil)
 	assert.NoError(t, err)
 
 	rr := httptest.NewRecorder()
-	handler.GetUserBids(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.GetUserBids)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusUnauthorized, rr.Code)
 	var errorResponse map[string]string
@@ -315,7 +432,7 @@ func TestGetUserBids_UserNotFound(t *testing.T) {
 
 func TestGetBidsByTenderID_Success(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/446a0a79-ffdc-47ea-a91c-873f834c12a2/list?username=user1&limit=10&offset=0", nil)
 	assert.NoError(t, err)
@@ -323,7 +440,7 @@ func TestGetBidsByTenderID_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{tenderId}/list", handler.GetBidsByTenderID).Methods("GET")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
@@ -336,7 +453,7 @@ func TestGetBidsByTenderID_Success(t *testing.T) {
 
 func TestGetBidsByTenderID_TenderNotFound(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/non-existent-tender-id/list?username=user1&limit=10&offset=0", nil)
 	assert.NoError(t, err)
@@ -344,7 +461,7 @@ func TestGetBidsByTenderID_TenderNotFound(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{tenderId}/list", handler.GetBidsByTenderID).Methods("GET")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
@@ -354,9 +471,35 @@ func TestGetBidsByTenderID_TenderNotFound(t *testing.T) {
 	assert.Equal(t, "Invalid tender ID format", errorResponse["reason"])
 }
 
+func TestGetBidsByTenderID_CursorPage(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/bids/446a0a79-ffdc-47ea-a91c-873f834c12a2/list?username=user1&limit=1&cursor=", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{tenderId}/list", handler.GetBidsByTenderID).Methods("GET")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var page struct {
+		Items      []models.Bid `json:"items"`
+		NextCursor string       `json:"nextCursor"`
+	}
+	err = json.NewDecoder(rr.Body).Decode(&page)
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+	cursor, err := repository.DecodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440099", cursor.ID)
+}
+
 func TestGetBidsByTenderID_InvalidOffset(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/446a0a79-ffdc-47ea-a91c-873f834c12a2/list?username=user1&limit=10&offset=-1", nil)
 	assert.NoError(t, err)
@@ -364,7 +507,7 @@ func TestGetBidsByTenderID_InvalidOffset(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{tenderId}/list", handler.GetBidsByTenderID).Methods("GET")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
@@ -376,7 +519,7 @@ func TestGetBidsByTenderID_InvalidOffset(t *testing.T) {
 
 func TestGetBidsByTenderID_ZeroLimit(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/446a0a79-ffdc-47ea-a91c-873f834c12a2/list?username=user1&limit=0&offset=0", nil)
 	assert.NoError(t, err)
@@ -384,7 +527,7 @@ func TestGetBidsByTenderID_ZeroLimit(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{tenderId}/list", handler.GetBidsByTenderID).Methods("GET")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
@@ -396,7 +539,7 @@ func TestGetBidsByTenderID_ZeroLimit(t *testing.T) {
 
 func TestGetBidStatus_Success(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/550e8400-e29b-41d4-a716-446655440099/status?username=user1", nil)
 	assert.NoError(t, err)
@@ -404,9 +547,10 @@ func TestGetBidStatus_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/status", handler.GetBidStatus).Methods("GET")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
 
 	expectedStatus := "CREATED"
 	assert.Equal(t, expectedStatus, rr.Body.String())
@@ -414,7 +558,7 @@ func TestGetBidStatus_Success(t *testing.T) {
 
 func TestGetBidStatus_BidNotFound(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/550e8400-e29b-41d4-a716-4466554400ff/status?username=user1", nil)
 	assert.NoError(t, err)
@@ -422,7 +566,7 @@ func TestGetBidStatus_BidNotFound(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/status", handler.GetBidStatus).Methods("GET")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 
@@ -434,7 +578,7 @@ func TestGetBidStatus_BidNotFound(t *testing.T) {
 
 func TestGetBidStatus_Forbidden(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/bids/550e8400-e29b-41d4-a716-446655440008/status?username=unauthorized-user", nil)
 	assert.NoError(t, err)
@@ -442,7 +586,7 @@ func TestGetBidStatus_Forbidden(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/status", handler.GetBidStatus).Methods("GET")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusForbidden, rr.Code)
 
@@ -454,7 +598,7 @@ func TestGetBidStatus_Forbidden(t *testing.T) {
 
 func TestUpdateBidStatus_Success(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/status?status=PUBLISHED&username=user1", nil)
 	assert.NoError(t, err)
@@ -462,14 +606,14 @@ func TestUpdateBidStatus_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/status", handler.UpdateBidStatus).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
 
 func TestUpdateBidStatus_BidNotFound(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/bids/non-existent-bid-id/status?status=PUBLISHED&username=user1", nil)
 	assert.NoError(t, err)
@@ -477,7 +621,7 @@ func TestUpdateBidStatus_BidNotFound(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/status", handler.UpdateBidStatus).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 	var errorResponse map[string]string
@@ -488,7 +632,7 @@ func TestUpdateBidStatus_BidNotFound(t *testing.T) {
 
 func TestUpdateBidStatus_Forbidden(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440008/status?status=PUBLISHED&username=unauthorized-user", nil)
 	assert.NoError(t, err)
@@ -496,7 +640,7 @@ func TestUpdateBidStatus_Forbidden(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/status", handler.UpdateBidStatus).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusForbidden, rr.Code)
 
@@ -506,9 +650,40 @@ func TestUpdateBidStatus_Forbidden(t *testing.T) {
 	assert.Equal(t, "Insufficient permissions", errorResponse["reason"])
 }
 
+func TestUpdateBidStatus_TransitionNotAllowed(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/status?status=APPROVED&username=user1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/status", handler.UpdateBidStatus).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestEditBid_NotEditable(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	body, _ := json.Marshal(map[string]interface{}{"description": "Updated"})
+	req, err := http.NewRequest("PATCH", "/api/bids/550e8400-e29b-41d4-a716-446655440066/edit?username=user1", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/edit", handler.EditBid).Methods("PATCH")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
 func TestEditBid_Success(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"description": "Updated description for this bid",
@@ -522,14 +697,52 @@ func TestEditBid_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/edit", handler.EditBid).Methods("PATCH")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+}
+
+func TestEditBid_VersionConflict(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	body, _ := json.Marshal(map[string]interface{}{"description": "Updated"})
+	req, err := http.NewRequest("PATCH", "/api/bids/550e8400-e29b-41d4-a716-446655440099/edit?username=user1", bytes.NewBuffer(body))
+	assert.NoError(t, err)
+	req.Header.Set("If-Match", `W/"5"`)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/edit", handler.EditBid).Methods("PATCH")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var errorResponse map[string]string
+	err = json.NewDecoder(rr.Body).Decode(&errorResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, "Version conflict", errorResponse["reason"])
+}
+
+func TestUpdateBidStatus_VersionConflict(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/status?status=PUBLISHED&username=user1&expectedVersion=4", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/status", handler.UpdateBidStatus).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
 }
 
 func TestEditBid_InvalidBidIDFormat(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"description": "Updated description for this bid",
@@ -543,7 +756,7 @@ func TestEditBid_InvalidBidIDFormat(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/edit", handler.EditBid).Methods("PATCH")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
@@ -555,7 +768,7 @@ func TestEditBid_InvalidBidIDFormat(t *testing.T) {
 
 func TestEditBid_Forbidden(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	requestBody := map[string]interface{}{
 		"description": "Updated description for this bid",
@@ -569,7 +782,7 @@ func TestEditBid_Forbidden(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/edit", handler.EditBid).Methods("PATCH")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusForbidden, rr.Code)
 
@@ -581,7 +794,7 @@ func TestEditBid_Forbidden(t *testing.T) {
 
 func TestSubmitBidFeedback_Success(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/feedback?username=user1&bidFeedback=Great%20job", nil)
 	assert.NoError(t, err)
@@ -589,7 +802,7 @@ func TestSubmitBidFeedback_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/feedback", handler.SubmitBidFeedback).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 
@@ -601,7 +814,7 @@ func TestSubmitBidFeedback_Success(t *testing.T) {
 
 func TestSubmitBidFeedback_BidNotFound(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/bids/non-existent-bid-id/feedback?username=user1&bidFeedback=Great%20job", nil)
 	assert.NoError(t, err)
@@ -609,7 +822,7 @@ func TestSubmitBidFeedback_BidNotFound(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/feedback", handler.SubmitBidFeedback).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
@@ -621,7 +834,7 @@ func TestSubmitBidFeedback_BidNotFound(t *testing.T) {
 
 func TestSubmitBidFeedback_NoFeedback(t *testing.T) {
 	mockService := &MockBidService{}
-	handler := NewBidHandler(mockService)
+	handler := NewBidHandler(mockService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/feedback?username=user1", nil)
 	assert.NoError(t, err)
@@ -629,7 +842,7 @@ func TestSubmitBidFeedback_NoFeedback(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/bids/{bidId}/feedback", handler.SubmitBidFeedback).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 
@@ -638,3 +851,200 @@ func TestSubmitBidFeedback_NoFeedback(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Feedback is required", response["reason"])
 }
+
+func TestSubmitBidDecision_Rejected(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/submit_decision?username=user1&decision=Rejected", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/submit_decision", handler.SubmitBidDecision).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+
+	var response map[string]interface{}
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440099", response["id"])
+	assert.Equal(t, string(models.BidStatusRejected), response["status"])
+}
+
+func TestSubmitBidDecision_InvalidDecision(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/submit_decision?username=user1&decision=Maybe", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/submit_decision", handler.SubmitBidDecision).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var response map[string]string
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Invalid bid decision", response["reason"])
+}
+
+func TestSubmitBidDecision_Forbidden(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/submit_decision?username=unauthorized-user&decision=Approved", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/submit_decision", handler.SubmitBidDecision).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestSubmitBidDecision_AlreadySubmitted(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/submit_decision?username=second-approver&decision=Approved", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/submit_decision", handler.SubmitBidDecision).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestSubmitBidDecision_VersionConflict(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/submit_decision?username=concurrent-approver&decision=Approved", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/submit_decision", handler.SubmitBidDecision).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestRollbackBid_Success(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/rollback/2?username=user1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/rollback/{version}", handler.RollbackBid).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440099", response["id"])
+	assert.Equal(t, float64(3), response["version"])
+}
+
+func TestRollbackBid_InvalidVersion(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/rollback/0?username=user1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/rollback/{version}", handler.RollbackBid).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestRollbackBid_NonexistentVersion(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/bids/550e8400-e29b-41d4-a716-446655440099/rollback/999?username=user1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{bidId}/rollback/{version}", handler.RollbackBid).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	var response map[string]string
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bid version not found", response["reason"])
+}
+
+func TestGetBidReviews_Success(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/bids/446a0a79-ffdc-47ea-a91c-873f834c12a2/reviews?authorUsername=user2&requesterUsername=user1&limit=5&offset=0", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{tenderId}/reviews", handler.GetBidReviews).Methods("GET")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	var reviews []map[string]interface{}
+	err = json.NewDecoder(rr.Body).Decode(&reviews)
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440077", reviews[0]["id"])
+	assert.Equal(t, "All good", reviews[0]["description"])
+	assert.Contains(t, reviews[0], "createdAt")
+	assert.NotContains(t, reviews[0], "bidId")
+}
+
+func TestGetBidReviews_AnonymousRequester(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/bids/446a0a79-ffdc-47ea-a91c-873f834c12a2/reviews?authorUsername=user2", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{tenderId}/reviews", handler.GetBidReviews).Methods("GET")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestGetBidReviews_Forbidden(t *testing.T) {
+	mockService := &MockBidService{}
+	handler := NewBidHandler(mockService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/bids/446a0a79-ffdc-47ea-a91c-873f834c12a2/reviews?authorUsername=user2&requesterUsername=unauthorized-user", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/bids/{tenderId}/reviews", handler.GetBidReviews).Methods("GET")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	my_errors "tender-service/internal/errors"
+)
+
+// expectedVersion reads the version the client based its change on, either
+// from the If-Match header or the expectedVersion query parameter. Zero means
+// the client sent neither and the change is applied unconditionally.
+func expectedVersion(r *http.Request) (int, error) {
+	raw := r.Header.Get("If-Match")
+	if raw == "" {
+		raw = r.URL.Query().Get("expectedVersion")
+	}
+	if raw == "" || raw == "*" {
+		return 0, nil
+	}
+
+	raw = strings.TrimPrefix(raw, "W/")
+	raw = strings.Trim(raw, `"`)
+
+	version, err := strconv.Atoi(raw)
+	if err != nil || version <= 0 {
+		return 0, my_errors.ErrBadRequest
+	}
+	return version, nil
+}
+
+func setETag(w http.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
@@ -0,0 +1,1002 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"tender-service/internal/auth"
+	"tender-service/internal/event"
+	"tender-service/internal/logging"
+	"tender-service/internal/models"
+	"tender-service/internal/outbox"
+	"tender-service/internal/repository/memory"
+	"tender-service/internal/server"
+	"tender-service/internal/service"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	e2eTenderOrganizationID = "550e8400-e29b-41d4-a716-446655440020"
+	e2eBidderOrganizationID = "550e8400-e29b-41d4-a716-446655440022"
+)
+
+const e2ePassword = "correct horse battery staple"
+
+// newMemoryRouter serves the API over fresh in-memory storage with the
+// username compatibility mode on, so tests may act as anyone by name.
+func newMemoryRouter(t *testing.T) *mux.Router {
+	t.Helper()
+	return newMemoryRouterWithAuth(t, true)
+}
+
+// newMemoryRouterWithAuth is newMemoryRouter with the compatibility mode
+// configurable. Every employee's password is e2ePassword.
+func newMemoryRouterWithAuth(t *testing.T, allowUsername bool) *mux.Router {
+	t.Helper()
+	router, _ := newMemoryRouterWithOutbox(t, allowUsername)
+	return router
+}
+
+// newMemoryRouterWithOutbox is newMemoryRouterWithAuth that also returns a
+// relay over the outbox the services write to. The relay has no sinks and is
+// not running; alice is the only admin.
+func newMemoryRouterWithOutbox(t *testing.T, allowUsername bool) (*mux.Router, *outbox.Relay) {
+	t.Helper()
+
+	store := memory.NewStore()
+	for _, employee := range []struct {
+		id, username, organizationID string
+	}{
+		{"550e8400-e29b-41d4-a716-446655440001", "alice", e2eTenderOrganizationID},
+		{"550e8400-e29b-41d4-a716-446655440002", "carol", e2eTenderOrganizationID},
+		{"550e8400-e29b-41d4-a716-446655440003", "bob", e2eBidderOrganizationID},
+	} {
+		store.AddOrganization(models.Organization{ID: employee.organizationID, Name: employee.organizationID, Type: models.OrganizationLLC})
+		store.AddEmployee(models.User{ID: employee.id, Username: employee.username})
+		store.AddOrganizationResponsible(employee.organizationID, employee.id)
+		require.NoError(t, store.SetPassword(employee.id, e2ePassword))
+	}
+
+	tenderRepo := memory.NewTenderRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	bidRepo := memory.NewBidRepository(store)
+	organizationRepo := memory.NewOrganizationRepository(store)
+	outboxRepo := memory.NewOutboxRepository(store)
+	unitOfWork := memory.NewUnitOfWork(store)
+
+	tenderTransitions := models.NewTenderStateMachine()
+	tenderTransitions.AddGuard(models.Created, models.Published, models.RequireTenderDescription)
+	tenderTransitions.AddGuard(models.Published, models.Closed, service.NoPendingBids)
+
+	userService := service.NewUserService(userRepo, organizationRepo, unitOfWork, []string{"alice"}, logging.Discard())
+	tenderService := service.NewTenderService(tenderRepo, userService, tenderTransitions, unitOfWork, logging.Discard())
+	bidService := service.NewBidService(bidRepo, tenderRepo, userRepo, unitOfWork, logging.Discard())
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo, logging.Discard())
+	outboxService := service.NewOutboxService(outboxRepo, userRepo, []string{"alice"})
+	webhookService := service.NewWebhookService(memory.NewWebhookRepository(store), organizationRepo, userRepo, logging.Discard())
+
+	tokens := auth.NewTokenManager([]byte("e2e-token-key"), time.Hour)
+	authService := service.NewAuthService(userRepo, tokens, logging.Discard())
+
+	return NewRouter(RouterDeps{
+		TenderService:       tenderService,
+		BidService:          bidService,
+		UserService:         userService,
+		AuthService:         authService,
+		OrganizationService: organizationService,
+		OutboxService:       outboxService,
+		WebhookService:      webhookService,
+		Tokens:              tokens,
+		AllowUsername:       allowUsername,
+		Health:              server.NewHealth(),
+		Logger:              logging.Discard(),
+	}), outbox.NewRelay(outboxRepo, time.Second, logging.Discard())
+}
+
+func serve(router *mux.Router, method, url string, body interface{}) *httptest.ResponseRecorder {
+	return serveWithToken(router, "", method, url, body)
+}
+
+// serveWithToken is serve with the request authenticated by token, unless it
+// is empty.
+func serveWithToken(router *mux.Router, token, method, url string, body interface{}) *httptest.ResponseRecorder {
+	var payload bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&payload).Encode(body)
+	}
+	req := httptest.NewRequest(method, url, &payload)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+// issueToken signs username in with e2ePassword.
+func issueToken(t *testing.T, router *mux.Router, username string) string {
+	t.Helper()
+	rr := serve(router, "POST", "/api/auth/token", map[string]string{"username": username, "password": e2ePassword})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var issued struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&issued))
+	return issued.Token
+}
+
+func TestMemoryStorage_TenderAndBidLifecycle(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     "Delivery",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+	assert.Equal(t, 1, tender.Version)
+
+	var tenders []models.Tender
+	rr = serve(router, "GET", "/api/tenders", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tenders))
+	assert.Empty(t, tenders)
+
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=PUBLISHED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "GET", "/api/tenders", nil)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tenders))
+	assert.Len(t, tenders, 1)
+	assert.Equal(t, 2, tenders[0].Version)
+
+	rr = serve(router, "POST", "/api/bids/new", map[string]interface{}{
+		"description":    "We can deliver",
+		"tenderId":       tender.ID,
+		"organizationId": e2eBidderOrganizationID,
+		"userId":         "550e8400-e29b-41d4-a716-446655440003",
+		"authorType":     "User",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var bid models.Bid
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bid))
+
+	var bids []models.Bid
+	rr = serve(router, "GET", "/api/bids/"+tender.ID+"/list?username=alice&limit=5&offset=0", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bids))
+	assert.Empty(t, bids)
+
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/status?status=PUBLISHED&username=bob", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "GET", "/api/bids/"+tender.ID+"/list?username=alice&limit=5&offset=0", nil)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bids))
+	assert.Len(t, bids, 1)
+
+	rr = serve(router, "GET", "/api/bids/my?username=bob", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bids))
+	assert.Len(t, bids, 1)
+
+	rr = serve(router, "GET", "/api/bids/"+bid.ID+"/status?username=bob", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, string(models.BidStatusPublished), rr.Body.String())
+
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=CLOSED&username=alice", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/submit_decision?decision=Approved&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/submit_decision?decision=Approved&username=carol", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var decided map[string]interface{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&decided))
+	assert.Equal(t, string(models.BidStatusApproved), decided["status"])
+
+	rr = serve(router, "GET", "/api/tenders/"+tender.ID+"/status?username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var status models.TenderStatus
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&status))
+	assert.Equal(t, models.Closed, status)
+}
+
+func TestMemoryStorage_SecondBidCannotBeApproved(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     "Delivery",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=PUBLISHED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	bidIDs := make([]string, 2)
+	for i := range bidIDs {
+		rr = serve(router, "POST", "/api/bids/new?username=bob", map[string]interface{}{
+			"description":    "We can deliver",
+			"tenderId":       tender.ID,
+			"organizationId": e2eBidderOrganizationID,
+			"authorType":     "User",
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+		var bid models.Bid
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&bid))
+		bidIDs[i] = bid.ID
+		rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/status?status=PUBLISHED&username=bob", nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	for _, username := range []string{"alice", "carol"} {
+		rr = serve(router, "PUT", "/api/bids/"+bidIDs[0]+"/submit_decision?decision=Approved&username="+username, nil)
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	rr = serve(router, "PUT", "/api/bids/"+bidIDs[1]+"/submit_decision?decision=Approved&username=alice", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	var bids []models.Bid
+	rr = serve(router, "GET", "/api/bids/"+tender.ID+"/list?username=alice&limit=5&offset=0", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bids))
+	statuses := map[string]models.BidStatus{}
+	for _, bid := range bids {
+		statuses[bid.ID] = bid.Status
+	}
+	assert.Equal(t, map[string]models.BidStatus{bidIDs[0]: models.BidStatusApproved, bidIDs[1]: models.BidStatusPublished}, statuses)
+}
+
+func TestMemoryStorage_TenderRollback(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Original",
+		"description":     "Original description",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+
+	rr = serve(router, "PATCH", "/api/tenders/"+tender.ID+"/edit?username=alice", map[string]interface{}{"name": "Renamed"})
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "POST", "/api/tenders/"+tender.ID+"/rollback/1?username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "GET", "/api/tenders?username=alice", nil)
+	var tenders []models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tenders))
+	require.Len(t, tenders, 1)
+	assert.Equal(t, "Original", tenders[0].Name)
+	assert.Equal(t, "Original description", tenders[0].Description)
+	assert.Equal(t, 3, tenders[0].Version)
+
+	rr = serve(router, "PATCH", "/api/tenders/"+tender.ID+"/edit?username=bob", map[string]interface{}{"name": "Hijacked"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	// Outsiders cannot tell which versions exist.
+	for _, version := range []string{"1", "99"} {
+		rr = serve(router, "POST", "/api/tenders/"+tender.ID+"/rollback/"+version+"?username=bob", nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code, version)
+		rr = serve(router, "POST", "/api/tenders/"+tender.ID+"/rollback/"+version, nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code, version)
+	}
+	rr = serve(router, "POST", "/api/tenders/"+tender.ID+"/rollback/99?username=alice", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestMemoryStorage_ConcurrentTenderEdits(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Original",
+		"description":     "Original description",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	etag := rr.Header().Get("ETag")
+	assert.Equal(t, `"1"`, etag)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+
+	edit := func(username, name string) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		json.NewEncoder(&payload).Encode(map[string]interface{}{"name": name})
+		req := httptest.NewRequest("PATCH", "/api/tenders/"+tender.ID+"/edit?username="+username, &payload)
+		req.Header.Set("If-Match", etag)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr = edit("alice", "First")
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+
+	rr = edit("carol", "Second")
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serve(router, "GET", "/api/tenders?username=alice", nil)
+	var tenders []models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tenders))
+	require.Len(t, tenders, 1)
+	assert.Equal(t, "First", tenders[0].Name)
+}
+
+func TestMemoryStorage_BearerTokenAuth(t *testing.T) {
+	router := newMemoryRouterWithAuth(t, false)
+
+	rr := serve(router, "POST", "/api/auth/token", map[string]string{"username": "alice", "password": "wrong"})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = serve(router, "POST", "/api/auth/token", map[string]string{"username": "alice", "password": e2ePassword})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var issued struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&issued))
+	require.NotEmpty(t, issued.Token)
+
+	createTender := func(authorization string, body map[string]interface{}) *httptest.ResponseRecorder {
+		var payload bytes.Buffer
+		json.NewEncoder(&payload).Encode(body)
+		req := httptest.NewRequest("POST", "/api/tenders/new", &payload)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	tender := map[string]interface{}{
+		"name":           "Delivery",
+		"description":    "Deliver goods",
+		"status":         "CREATED",
+		"organizationId": e2eTenderOrganizationID,
+	}
+
+	rr = createTender("Bearer "+issued.Token, tender)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var created models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&created))
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440001", created.CreatorID)
+
+	rr = createTender("Bearer "+issued.Token+"x", tender)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	// Without the compatibility mode a claimed username is ignored.
+	tender["creatorUsername"] = "alice"
+	rr = createTender("", tender)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = serve(router, "PATCH", "/api/tenders/"+created.ID+"/edit?username=alice", map[string]interface{}{"name": "Hijacked"})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestMemoryStorage_OrganizationMembership(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	rr := serve(router, "POST", "/api/organizations/new?username=alice", map[string]interface{}{
+		"name": "Acme",
+		"type": "LLC",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var organization models.Organization
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&organization))
+	assert.Equal(t, models.OrganizationLLC, organization.Type)
+	responsibles := "/api/organizations/" + organization.ID + "/responsibles"
+
+	rr = serve(router, "POST", "/api/organizations/new?username=alice", map[string]interface{}{
+		"name": "Acme",
+		"type": "PLC",
+	})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serve(router, "POST", responsibles+"?username=bob", map[string]string{"username": "bob"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+
+	rr = serve(router, "POST", responsibles+"?username=alice", map[string]string{"username": "bob"})
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = serve(router, "POST", responsibles+"?username=alice", map[string]string{"username": "bob"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var members []models.User
+	rr = serve(router, "GET", responsibles+"?username=carol", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&members))
+	require.Len(t, members, 2)
+	assert.Equal(t, "alice", members[0].Username)
+	assert.Equal(t, "bob", members[1].Username)
+
+	var organizations []models.Organization
+	rr = serve(router, "GET", "/api/organizations/my?username=bob", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&organizations))
+	assert.Contains(t, organizations, organization)
+
+	rr = serve(router, "DELETE", responsibles+"/alice?username=carol", nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = serve(router, "DELETE", responsibles+"/alice?username=bob", nil)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = serve(router, "DELETE", responsibles+"/bob?username=bob", nil)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serve(router, "GET", "/api/organizations/my?username=alice", nil)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&organizations))
+	assert.NotContains(t, organizations, organization)
+
+	rr = serve(router, "GET", "/api/organizations", nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestMemoryStorage_DeactivatedEmployee(t *testing.T) {
+	router := newMemoryRouter(t)
+	const carolID = "550e8400-e29b-41d4-a716-446655440002"
+
+	admin := issueToken(t, router, "alice")
+
+	rr := serveWithToken(router, issueToken(t, router, "bob"), "POST", "/api/users/new", map[string]string{"username": "dave"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	// A claimed username never grants admin rights.
+	rr = serve(router, "POST", "/api/users/new?username=alice", map[string]string{"username": "dave"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = serveWithToken(router, admin, "POST", "/api/users/new", map[string]string{
+		"username":   "dave",
+		"first_name": "Dave",
+		"password":   e2ePassword,
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = serveWithToken(router, admin, "POST", "/api/users/new", map[string]string{"username": "dave"})
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	rr = serve(router, "POST", "/api/users/"+carolID+"/deactivate?username=bob", nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	// Co-responsibles may not shrink the approval quorum.
+	rr = serve(router, "POST", "/api/users/"+carolID+"/deactivate?username=alice", nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = serveWithToken(router, admin, "POST", "/api/users/"+carolID+"/deactivate", nil)
+	require.Equal(t, http.StatusNoContent, rr.Code)
+
+	rr = serve(router, "GET", "/api/users/"+carolID+"?username=alice", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+
+	rr = serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Delivery",
+		"serviceType":     "Delivery",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "carol",
+	})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	rr = serve(router, "POST", "/api/auth/token", map[string]string{"username": "carol", "password": e2ePassword})
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+
+	var users []models.User
+	rr = serve(router, "GET", "/api/users?username=alice&search=a", nil)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&users))
+	assert.Len(t, users, 2)
+
+	rr = serve(router, "GET", "/api/users?username=alice&search=a&includeInactive=true&limit=2&offset=1", nil)
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&users))
+	require.Len(t, users, 2)
+	assert.Equal(t, "carol", users[0].Username)
+	assert.False(t, users[0].Active)
+	assert.Equal(t, "dave", users[1].Username)
+
+	// With carol deactivated, alice is the only responsible left to decide.
+	rr = serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     "Delivery",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=PUBLISHED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = serve(router, "POST", "/api/bids/new?username=bob", map[string]interface{}{
+		"description":    "We can deliver",
+		"tenderId":       tender.ID,
+		"organizationId": e2eBidderOrganizationID,
+		"authorType":     "User",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var bid models.Bid
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bid))
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/status?status=PUBLISHED&username=bob", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/submit_decision?decision=Approved&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var decided map[string]interface{}
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&decided))
+	assert.Equal(t, string(models.BidStatusApproved), decided["status"])
+}
+
+func TestMemoryStorage_TenderListOptions(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	for _, tender := range []struct{ name, serviceType string }{
+		{"Cement", "Construction"},
+		{"Asphalt", "Construction"},
+		{"Boxes", "Delivery"},
+		{"Drills", "Manufacture"},
+	} {
+		rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+			"name":            tender.name,
+			"description":     tender.name,
+			"serviceType":     tender.serviceType,
+			"status":          "CREATED",
+			"organizationId":  e2eTenderOrganizationID,
+			"creatorUsername": "alice",
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+	}
+
+	names := func(url string) []string {
+		rr := serve(router, "GET", url, nil)
+		require.Equal(t, http.StatusOK, rr.Code, url)
+		var tenders []models.Tender
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&tenders))
+		var names []string
+		for _, tender := range tenders {
+			names = append(names, tender.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"Asphalt", "Boxes", "Cement", "Drills"}, names("/api/tenders/my?username=alice"))
+	assert.Equal(t, []string{"Asphalt", "Boxes"}, names("/api/tenders/my?username=alice&limit=2"))
+	assert.Equal(t, []string{"Cement", "Drills"}, names("/api/tenders/my?username=alice&limit=2&offset=2"))
+	assert.Equal(t, []string{"Cement", "Boxes", "Asphalt"}, names("/api/tenders/my?username=alice&service_type=Construction,Delivery&order=desc"))
+	assert.Equal(t, []string{"Drills", "Boxes", "Asphalt", "Cement"}, names("/api/tenders/my?username=alice&sort=createdAt&order=desc"))
+	assert.Empty(t, names("/api/tenders/my?username=alice&status=PUBLISHED"))
+	assert.Empty(t, names("/api/tenders/my?username=alice&createdFrom=2999-01-01"))
+
+	// Like the SQL filter, a service type filter drops tenders without one.
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Misc",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, []string{"Boxes"}, names("/api/tenders/my?username=alice&service_type=Delivery"))
+
+	rr = serve(router, "GET", "/api/tenders/my?username=alice&status=OPEN", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMemoryStorage_CursorPagination(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	var created []string
+	for _, name := range []string{"Delta", "Alpha", "Charlie", "Bravo", "Echo"} {
+		rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+			"name":            name,
+			"serviceType":     "Delivery",
+			"status":          "CREATED",
+			"organizationId":  e2eTenderOrganizationID,
+			"creatorUsername": "alice",
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+		created = append(created, name)
+	}
+
+	page := func(url string) ([]string, string) {
+		rr := serve(router, "GET", url, nil)
+		require.Equal(t, http.StatusOK, rr.Code, url)
+		var response struct {
+			Items      []models.Tender `json:"items"`
+			NextCursor string          `json:"nextCursor"`
+		}
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+		var names []string
+		for _, tender := range response.Items {
+			names = append(names, tender.Name)
+		}
+		return names, response.NextCursor
+	}
+
+	var names []string
+	url := "/api/tenders/my?username=alice&limit=2&cursor="
+	for {
+		pageNames, next := page(url)
+		names = append(names, pageNames...)
+		if next == "" {
+			break
+		}
+		url = "/api/tenders/my?username=alice&limit=2&cursor=" + next
+	}
+	assert.Equal(t, created, names)
+
+	first, next := page("/api/tenders/my?username=alice&limit=3&order=desc&cursor=")
+	assert.Equal(t, []string{"Echo", "Bravo", "Charlie"}, first)
+	rest, next := page("/api/tenders/my?username=alice&limit=3&order=desc&cursor=" + next)
+	assert.Equal(t, []string{"Alpha", "Delta"}, rest)
+	assert.Empty(t, next)
+
+	tampered := base64.RawURLEncoding.EncodeToString([]byte(time.Now().UTC().Format(time.RFC3339Nano) + "|abc"))
+	for _, query := range []string{"cursor=bogus", "cursor=" + tampered, "cursor=&offset=2", "cursor=&sort=name"} {
+		rr := serve(router, "GET", "/api/tenders/my?username=alice&"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+
+	rr := serve(router, "GET", "/api/tenders/my?username=alice&limit=2", nil)
+	var tenders []models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tenders))
+	assert.Len(t, tenders, 2)
+}
+
+func TestMemoryStorage_Search(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	var tenders []models.Tender
+	for _, tender := range []struct{ name, description string }{
+		{"Road repair", "Patch the road to the warehouse"},
+		{"Office cleaning", "Clean the offices along the road"},
+		{"Roadside signs", "Paint new signs"},
+	} {
+		rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+			"name":            tender.name,
+			"description":     tender.description,
+			"serviceType":     "Construction",
+			"status":          "CREATED",
+			"organizationId":  e2eTenderOrganizationID,
+			"creatorUsername": "alice",
+		})
+		require.Equal(t, http.StatusOK, rr.Code)
+		var created models.Tender
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&created))
+		tenders = append(tenders, created)
+	}
+	rr := serve(router, "PUT", "/api/tenders/"+tenders[0].ID+"/status?status=PUBLISHED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	searchTenders := func(url string) []models.TenderSearchResult {
+		rr := serve(router, "GET", url, nil)
+		require.Equal(t, http.StatusOK, rr.Code, url)
+		var results []models.TenderSearchResult
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&results))
+		return results
+	}
+
+	results := searchTenders("/api/tenders/search?q=road&username=alice")
+	require.Len(t, results, 2)
+	assert.Equal(t, "Road repair", results[0].Name)
+	assert.Equal(t, "Office cleaning", results[1].Name)
+	assert.Greater(t, results[0].Rank, results[1].Rank)
+	assert.Equal(t, "<b>Road</b> repair Patch the <b>road</b> to the warehouse", results[0].Highlight)
+
+	results = searchTenders("/api/tenders/search?q=road")
+	require.Len(t, results, 1)
+	assert.Equal(t, "Road repair", results[0].Name)
+
+	assert.Len(t, searchTenders("/api/tenders/search?q=road+warehouse&username=alice"), 1)
+	assert.Empty(t, searchTenders("/api/tenders/search?q=road&username=bob&status=CREATED"))
+
+	rr = serve(router, "POST", "/api/bids/new", map[string]interface{}{
+		"description":    "Fresh asphalt for the whole road <script>alert(1)</script>",
+		"tenderId":       tenders[0].ID,
+		"organizationId": e2eBidderOrganizationID,
+		"userId":         "550e8400-e29b-41d4-a716-446655440003",
+		"authorType":     "User",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var bid models.Bid
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bid))
+
+	searchBids := func(url string) []models.BidSearchResult {
+		rr := serve(router, "GET", url, nil)
+		require.Equal(t, http.StatusOK, rr.Code, url)
+		var results []models.BidSearchResult
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&results))
+		return results
+	}
+
+	bids := searchBids("/api/bids/search?q=asphalt&username=bob")
+	require.Len(t, bids, 1)
+	// Only the highlight markup is left unescaped.
+	assert.Equal(t, "Fresh <b>asphalt</b> for the whole road &lt;script&gt;alert(1)&lt;/script&gt;", bids[0].Highlight)
+	assert.Empty(t, searchBids("/api/bids/search?q=asphalt&username=alice"))
+
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/status?status=PUBLISHED&username=bob", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Len(t, searchBids("/api/bids/search?q=asphalt&username=alice"), 1)
+
+	rr = serve(router, "GET", "/api/bids/search?q=asphalt", nil)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	rr = serve(router, "GET", "/api/bids/search?q=+&username=bob", nil)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestMemoryStorage_SubmissionDeadline(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	submission := time.Now().Add(-time.Minute).UTC()
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":               "Delivery",
+		"description":        "Deliver equipment",
+		"serviceType":        "Delivery",
+		"status":             "CREATED",
+		"organizationId":     e2eTenderOrganizationID,
+		"creatorUsername":    "alice",
+		"submissionDeadline": submission,
+		"decisionDeadline":   submission.Add(-time.Hour),
+	})
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	rr = serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":               "Delivery",
+		"description":        "Deliver equipment",
+		"serviceType":        "Delivery",
+		"status":             "CREATED",
+		"organizationId":     e2eTenderOrganizationID,
+		"creatorUsername":    "alice",
+		"submissionDeadline": submission,
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+	require.NotNil(t, tender.SubmissionDeadline)
+	assert.True(t, submission.Equal(*tender.SubmissionDeadline))
+
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=PUBLISHED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	bid := map[string]interface{}{
+		"description":    "We can deliver",
+		"tenderId":       tender.ID,
+		"organizationId": e2eBidderOrganizationID,
+		"userId":         "550e8400-e29b-41d4-a716-446655440003",
+		"authorType":     "User",
+	}
+	rr = serve(router, "POST", "/api/bids/new", bid)
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	req := httptest.NewRequest("PATCH", "/api/tenders/"+tender.ID+"/edit?username=alice", bytes.NewBufferString(
+		`{"submissionDeadline": "`+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`"}`))
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "POST", "/api/bids/new", bid)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestMemoryStorage_BidsNeedPublishedTender(t *testing.T) {
+	router := newMemoryRouter(t)
+
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     "Delivery",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+
+	bid := map[string]interface{}{
+		"description":    "We can deliver",
+		"tenderId":       tender.ID,
+		"organizationId": e2eBidderOrganizationID,
+		"userId":         "550e8400-e29b-41d4-a716-446655440003",
+		"authorType":     "User",
+	}
+	rr = serve(router, "POST", "/api/bids/new", bid)
+	assert.Equal(t, http.StatusConflict, rr.Code, "unpublished tender")
+
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=PUBLISHED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=CLOSED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "POST", "/api/bids/new", bid)
+	assert.Equal(t, http.StatusConflict, rr.Code, "closed tender")
+
+	bid["tenderId"] = "550e8400-e29b-41d4-a716-446655449999"
+	rr = serve(router, "POST", "/api/bids/new", bid)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestMemoryStorage_LifecycleEvents(t *testing.T) {
+	var events []event.Event
+	bus := event.NewBus()
+	bus.Subscribe(event.SubscriberFunc(func(ctx context.Context, e event.Event) error {
+		events = append(events, e)
+		return nil
+	}))
+	failed := false
+	bus.Subscribe(event.SubscriberFunc(func(ctx context.Context, e event.Event) error {
+		if failed {
+			return nil
+		}
+		failed = true
+		return errors.New("subscriber unavailable")
+	}))
+	router, relay := newMemoryRouterWithOutbox(t, true)
+	relay.Register(outbox.EventSink(bus))
+
+	rr := serve(router, "POST", "/api/tenders/new", map[string]interface{}{
+		"name":            "Delivery",
+		"description":     "Deliver equipment",
+		"serviceType":     "Delivery",
+		"status":          "CREATED",
+		"organizationId":  e2eTenderOrganizationID,
+		"creatorUsername": "alice",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var tender models.Tender
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&tender))
+
+	rr = serve(router, "PUT", "/api/tenders/"+tender.ID+"/status?status=PUBLISHED&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	rr = serve(router, "POST", "/api/bids/new", map[string]interface{}{
+		"description":    "We can deliver",
+		"tenderId":       tender.ID,
+		"organizationId": e2eBidderOrganizationID,
+		"userId":         "550e8400-e29b-41d4-a716-446655440003",
+		"authorType":     "User",
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var bid models.Bid
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&bid))
+
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/status?status=PUBLISHED&username=bob", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/feedback?bidFeedback=Fine&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	rr = serve(router, "PUT", "/api/bids/"+bid.ID+"/submit_decision?decision=Rejected&username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	assert.Empty(t, events, "events are delivered by the relay, not by requests")
+
+	// The first delivery fails for one subscriber, so the event stays pending
+	// and is delivered again, to every subscriber, once its backoff passes.
+	now := time.Now()
+	sent, err := relay.DeliverDue(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 6, sent)
+
+	admin := issueToken(t, router, "alice")
+	rr = serveWithToken(router, issueToken(t, router, "bob"), "GET", "/api/admin/outbox", nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = serve(router, "GET", "/api/admin/outbox?username=alice", nil)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = serveWithToken(router, admin, "GET", "/api/admin/outbox", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var pending []models.OutboxEntry
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&pending))
+	require.Len(t, pending, 1)
+	assert.Equal(t, string(event.TypeTenderCreated), pending[0].EventType)
+	assert.Equal(t, models.OutboxPending, pending[0].Status)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "subscriber unavailable", pending[0].LastError)
+
+	sent, err = relay.DeliverDue(context.Background(), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	rr = serveWithToken(router, admin, "GET", "/api/admin/outbox?status=SENT", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var delivered []models.OutboxEntry
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&delivered))
+	assert.Len(t, delivered, 7)
+
+	var types []event.Type
+	for _, e := range events {
+		types = append(types, e.Type())
+	}
+	assert.Equal(t, []event.Type{
+		event.TypeTenderCreated,
+		event.TypeTenderPublished,
+		event.TypeBidCreated,
+		event.TypeBidStatusChanged,
+		event.TypeFeedbackAdded,
+		event.TypeBidDecisionSubmitted,
+		event.TypeBidStatusChanged,
+		event.TypeTenderCreated,
+	}, types)
+
+	rejected, ok := events[6].(event.BidStatusChanged)
+	require.True(t, ok)
+	assert.Equal(t, models.BidStatusPublished, rejected.PreviousStatus)
+	assert.Equal(t, models.BidStatusRejected, rejected.Bid.Status)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440001", rejected.ActorID)
+	assert.Equal(t, bid.ID, rejected.SubjectID())
+}
+
+func TestMemoryStorage_Webhooks(t *testing.T) {
+	router := newMemoryRouter(t)
+	webhooks := "/api/organizations/" + e2eTenderOrganizationID + "/webhooks"
+
+	rr := serve(router, "POST", webhooks+"?username=alice", map[string]interface{}{
+		"url":        "https://example.com/hooks",
+		"eventTypes": []string{"bid.status_changed", "bid.decision_submitted"},
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var created models.WebhookSubscription
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&created))
+	assert.NotEmpty(t, created.Secret, "a secret is generated and returned once")
+	assert.True(t, created.Active)
+
+	for _, body := range []map[string]interface{}{
+		{"url": "ftp://example.com/hooks"},
+		{"url": "http://127.0.0.1:8080/api/admin/outbox"},
+		{"url": "http://localhost/hooks"},
+		{"url": "http://169.254.169.254/latest/meta-data"},
+		{"url": "http://[::1]/hooks"},
+		{"url": "https://10.0.0.5/hooks"},
+		{"url": "https://example.com/hooks", "eventTypes": []string{"bid.deleted"}},
+	} {
+		rr = serve(router, "POST", webhooks+"?username=alice", body)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	}
+
+	rr = serve(router, "POST", webhooks+"?username=bob", map[string]interface{}{"url": "https://example.com/hooks"})
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	rr = serve(router, "GET", "/api/organizations/"+e2eBidderOrganizationID+"/webhooks/"+created.ID+"?username=bob", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code, "webhooks of other organizations are not found")
+
+	rr = serve(router, "GET", webhooks+"?username=carol", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	var listed []models.WebhookSubscription
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&listed))
+	require.Len(t, listed, 1)
+	assert.Equal(t, created.ID, listed[0].ID)
+	assert.Empty(t, listed[0].Secret)
+
+	rr = serve(router, "PATCH", webhooks+"/"+created.ID+"?username=carol", map[string]interface{}{
+		"active":     false,
+		"eventTypes": []string{},
+	})
+	require.Equal(t, http.StatusOK, rr.Code)
+	var updated models.WebhookSubscription
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&updated))
+	assert.False(t, updated.Active)
+	assert.Empty(t, updated.EventTypes)
+	assert.Equal(t, created.URL, updated.URL)
+
+	rr = serve(router, "GET", webhooks+"/"+created.ID+"/deliveries?username=alice", nil)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, "[]", rr.Body.String())
+
+	rr = serve(router, "DELETE", webhooks+"/"+created.ID+"?username=alice", nil)
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	rr = serve(router, "GET", webhooks+"/"+created.ID+"?username=alice", nil)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
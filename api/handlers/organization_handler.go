@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"tender-service/internal/service"
+	"tender-service/utils"
+
+	"github.com/gorilla/mux"
+
+	my_errors "tender-service/internal/errors"
+)
+
+type OrganizationHandler struct {
+	organizationService service.OrganizationService
+	logger              *slog.Logger
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService, logger *slog.Logger) *OrganizationHandler {
+	return &OrganizationHandler{organizationService: organizationService, logger: logger}
+}
+
+// writeError maps errors shared by all organization endpoints.
+func (h *OrganizationHandler) writeError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, my_errors.ErrUnauthorized):
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+	case errors.Is(err, my_errors.ErrForbidden):
+		utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+	case errors.Is(err, my_errors.ErrOrganizationNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Organization not found")
+	case errors.Is(err, my_errors.ErrUserNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Employee not found")
+	case errors.Is(err, my_errors.ErrResponsibleNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Employee is not responsible for the organization")
+	case errors.Is(err, my_errors.ErrResponsibleExists):
+		utils.WriteErrorResponse(w, http.StatusConflict, "Employee is already responsible for the organization")
+	case errors.Is(err, my_errors.ErrLastResponsible):
+		utils.WriteErrorResponse(w, http.StatusConflict, "Organization must keep at least one responsible")
+	case errors.Is(err, my_errors.ErrInvalidOrganizationType):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid organization type")
+	case errors.Is(err, my_errors.ErrBadRequest):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request data")
+	default:
+		h.logger.ErrorContext(r.Context(), "Error "+action, "error", err)
+		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error "+action)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, logger *slog.Logger, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		logger.ErrorContext(r.Context(), "Error encoding response", "error", err)
+	}
+}
+
+func (h *OrganizationHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Type        string `json:"type"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.Name == "" || request.Type == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing required fields")
+		return
+	}
+
+	organization, err := h.organizationService.CreateOrganization(r.Context(), request.Name, request.Description, request.Type)
+	if err != nil {
+		h.writeError(w, r, err, "creating organization")
+		return
+	}
+
+	writeJSON(w, r, h.logger, organization)
+}
+
+func (h *OrganizationHandler) GetOrganizations(w http.ResponseWriter, r *http.Request) {
+	organizations, err := h.organizationService.GetOrganizations(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "fetching organizations")
+		return
+	}
+
+	writeJSON(w, r, h.logger, organizations)
+}
+
+func (h *OrganizationHandler) GetUserOrganizations(w http.ResponseWriter, r *http.Request) {
+	organizations, err := h.organizationService.GetUserOrganizations(r.Context())
+	if err != nil {
+		h.writeError(w, r, err, "fetching user organizations")
+		return
+	}
+
+	writeJSON(w, r, h.logger, organizations)
+}
+
+func (h *OrganizationHandler) GetOrganization(w http.ResponseWriter, r *http.Request) {
+	organizationID := mux.Vars(r)["organizationId"]
+
+	organization, err := h.organizationService.GetOrganization(r.Context(), organizationID)
+	if err != nil {
+		h.writeError(w, r, err, "fetching organization")
+		return
+	}
+
+	writeJSON(w, r, h.logger, organization)
+}
+
+func (h *OrganizationHandler) GetResponsibles(w http.ResponseWriter, r *http.Request) {
+	organizationID := mux.Vars(r)["organizationId"]
+
+	responsibles, err := h.organizationService.GetResponsibles(r.Context(), organizationID)
+	if err != nil {
+		h.writeError(w, r, err, "fetching responsibles")
+		return
+	}
+
+	writeJSON(w, r, h.logger, responsibles)
+}
+
+func (h *OrganizationHandler) AddResponsible(w http.ResponseWriter, r *http.Request) {
+	organizationID := mux.Vars(r)["organizationId"]
+
+	var request struct {
+		Username string `json:"username"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.Username == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing required fields")
+		return
+	}
+
+	responsible, err := h.organizationService.AddResponsible(r.Context(), organizationID, request.Username)
+	if err != nil {
+		h.writeError(w, r, err, "adding responsible")
+		return
+	}
+
+	writeJSON(w, r, h.logger, responsible)
+}
+
+func (h *OrganizationHandler) RemoveResponsible(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	err := h.organizationService.RemoveResponsible(r.Context(), vars["organizationId"], vars["responsibleUsername"])
+	if err != nil {
+		h.writeError(w, r, err, "removing responsible")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/logging"
+	"tender-service/internal/models"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockOrganizationService struct{}
+
+func (m *MockOrganizationService) CreateOrganization(ctx context.Context, name, description, organizationType string) (models.Organization, error) {
+	if mockCaller(ctx) == "" {
+		return models.Organization{}, my_errors.ErrUnauthorized
+	}
+	if _, err := models.ParseOrganizationType(organizationType); err != nil {
+		return models.Organization{}, my_errors.ErrInvalidOrganizationType
+	}
+	return models.Organization{ID: "550e8400-e29b-41d4-a716-446655440020", Name: name, Type: models.OrganizationType(organizationType)}, nil
+}
+
+func (m *MockOrganizationService) GetOrganizations(ctx context.Context) ([]models.Organization, error) {
+	return []models.Organization{}, nil
+}
+
+func (m *MockOrganizationService) GetOrganization(ctx context.Context, organizationID string) (models.Organization, error) {
+	return models.Organization{}, my_errors.ErrOrganizationNotFound
+}
+
+func (m *MockOrganizationService) GetUserOrganizations(ctx context.Context) ([]models.Organization, error) {
+	return []models.Organization{}, nil
+}
+
+func (m *MockOrganizationService) GetResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	return []models.User{}, nil
+}
+
+func (m *MockOrganizationService) AddResponsible(ctx context.Context, organizationID, username string) (models.User, error) {
+	if mockCaller(ctx) != "test-user" {
+		return models.User{}, my_errors.ErrForbidden
+	}
+	return models.User{Username: username}, nil
+}
+
+func (m *MockOrganizationService) RemoveResponsible(ctx context.Context, organizationID, username string) error {
+	if username == mockCaller(ctx) {
+		return my_errors.ErrLastResponsible
+	}
+	return nil
+}
+
+func newOrganizationRouter() http.Handler {
+	handler := NewOrganizationHandler(&MockOrganizationService{}, logging.Discard())
+	router := mux.NewRouter()
+	router.HandleFunc("/api/organizations/new", handler.CreateOrganization).Methods("POST")
+	router.HandleFunc("/api/organizations/{organizationId}", handler.GetOrganization).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles", handler.AddResponsible).Methods("POST")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles/{responsibleUsername}", handler.RemoveResponsible).Methods("DELETE")
+	return withLegacyAuth(router)
+}
+
+func TestCreateOrganization(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/organizations/new?username=test-user", bytes.NewBufferString(`{"name":"Acme","type":"JSC"}`))
+	rr := httptest.NewRecorder()
+	newOrganizationRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"type":"JSC"`)
+}
+
+func TestCreateOrganization_InvalidType(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/organizations/new?username=test-user", bytes.NewBufferString(`{"name":"Acme","type":"GmbH"}`))
+	rr := httptest.NewRecorder()
+	newOrganizationRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Invalid organization type")
+}
+
+func TestCreateOrganization_Anonymous(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/organizations/new", bytes.NewBufferString(`{"name":"Acme","type":"IE"}`))
+	rr := httptest.NewRecorder()
+	newOrganizationRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestGetOrganization_NotFound(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/organizations/550e8400-e29b-41d4-a716-446655440099?username=test-user", nil)
+	rr := httptest.NewRecorder()
+	newOrganizationRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestAddResponsible_NotResponsible(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/organizations/550e8400-e29b-41d4-a716-446655440020/responsibles?username=outsider", bytes.NewBufferString(`{"username":"new-user"}`))
+	rr := httptest.NewRecorder()
+	newOrganizationRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestRemoveResponsible_LastResponsible(t *testing.T) {
+	req := httptest.NewRequest("DELETE", "/api/organizations/550e8400-e29b-41d4-a716-446655440020/responsibles/test-user?username=test-user", nil)
+	rr := httptest.NewRecorder()
+	newOrganizationRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"tender-service/internal/models"
+	"tender-service/internal/service"
+	"tender-service/utils"
+
+	my_errors "tender-service/internal/errors"
+)
+
+type OutboxHandler struct {
+	outboxService service.OutboxService
+	logger        *slog.Logger
+}
+
+func NewOutboxHandler(outboxService service.OutboxService, logger *slog.Logger) *OutboxHandler {
+	return &OutboxHandler{outboxService: outboxService, logger: logger}
+}
+
+// GetOutbox lists outbox entries. Without a status filter it shows the ones
+// still needing attention: pending and failed.
+func (h *OutboxHandler) GetOutbox(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	statuses := []string{string(models.OutboxPending), string(models.OutboxFailed)}
+	if status := query.Get("status"); status != "" {
+		statuses = strings.Split(status, ",")
+	}
+
+	entries, err := h.outboxService.ListOutbox(r.Context(), statuses, limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, my_errors.ErrUnauthorized):
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+		case errors.Is(err, my_errors.ErrForbidden):
+			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrBadRequest):
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid outbox status")
+		default:
+			h.logger.ErrorContext(r.Context(), "Error fetching outbox", "error", err)
+			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error fetching outbox")
+		}
+		return
+	}
+
+	writeJSON(w, r, h.logger, entries)
+}
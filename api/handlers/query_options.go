@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/repository"
+)
+
+const maxListLimit = 50
+
+// queryOptions reads the paging, filtering and sorting parameters shared by
+// list endpoints. Multi-value filters may be repeated or comma-separated.
+// defaultLimit applies when the request sets no limit.
+//
+// A cursor parameter, even an empty one, selects cursor mode: results are
+// ordered by creation time and paged with the nextCursor of the previous
+// response instead of an offset.
+func queryOptions(r *http.Request, defaultLimit int) (repository.QueryOptions, error) {
+	query := r.URL.Query()
+	options := repository.QueryOptions{
+		Limit:           defaultLimit,
+		Statuses:        listParam(query, "status"),
+		ServiceTypes:    listParam(query, "service_type"),
+		OrganizationIDs: listParam(query, "organizationId"),
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxListLimit {
+			return repository.QueryOptions{}, my_errors.ErrBadRequest
+		}
+		options.Limit = limit
+	}
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return repository.QueryOptions{}, my_errors.ErrBadRequest
+		}
+		options.Offset = offset
+	}
+
+	var err error
+	if options.CreatedFrom, err = timeParam(query.Get("createdFrom"), false); err != nil {
+		return repository.QueryOptions{}, err
+	}
+	if options.CreatedTo, err = timeParam(query.Get("createdTo"), true); err != nil {
+		return repository.QueryOptions{}, err
+	}
+
+	if options.SortBy, err = repository.ParseSortField(query.Get("sort")); err != nil {
+		return repository.QueryOptions{}, my_errors.ErrBadRequest
+	}
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		options.Descending = true
+	default:
+		return repository.QueryOptions{}, my_errors.ErrBadRequest
+	}
+
+	if cursorMode(r) {
+		if query.Has("offset") || (query.Has("sort") && options.SortBy != repository.SortByCreatedAt) {
+			return repository.QueryOptions{}, my_errors.ErrBadRequest
+		}
+		options.SortBy = repository.SortByCreatedAt
+		if token := query.Get("cursor"); token != "" {
+			cursor, err := repository.DecodeCursor(token)
+			if err != nil {
+				return repository.QueryOptions{}, my_errors.ErrBadRequest
+			}
+			options.After = &cursor
+		}
+	}
+
+	return options, nil
+}
+
+func cursorMode(r *http.Request) bool {
+	return r.URL.Query().Has("cursor")
+}
+
+type cursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// writeList writes items as a plain array in offset mode, which the API has
+// always returned, and as a page with nextCursor in cursor mode. A full page
+// gets a cursor; a short one is the last.
+func writeList[T any](w http.ResponseWriter, r *http.Request, logger *slog.Logger, items []T, options repository.QueryOptions, cursorOf func(T) repository.Cursor) {
+	var response interface{} = items
+	if cursorMode(r) {
+		page := cursorPage[T]{Items: items}
+		if page.Items == nil {
+			page.Items = []T{}
+		}
+		if options.Limit > 0 && len(items) == options.Limit {
+			page.NextCursor = cursorOf(items[len(items)-1]).Encode()
+		}
+		response = page
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.ErrorContext(r.Context(), "Error encoding response", "error", err)
+	}
+}
+
+func listParam(query url.Values, name string) []string {
+	var values []string
+	for _, raw := range query[name] {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// timeParam accepts RFC 3339 timestamps and plain dates. A plain date used as
+// the end of a range covers the whole day.
+func timeParam(raw string, rangeEnd bool) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return time.Time{}, my_errors.ErrBadRequest
+	}
+	if rangeEnd {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
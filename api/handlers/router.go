@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"tender-service/internal/auth"
+	"tender-service/internal/server"
+	"tender-service/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+// RouterDeps is what NewRouter serves the API with.
+type RouterDeps struct {
+	TenderService       service.TenderService
+	BidService          service.BidService
+	UserService         service.UserService
+	AuthService         service.AuthService
+	OrganizationService service.OrganizationService
+	OutboxService       service.OutboxService
+	WebhookService      service.WebhookService
+
+	Tokens *auth.TokenManager
+	// AllowUsername lets requests without a token identify themselves by
+	// username.
+	AllowUsername bool
+	Health        *server.Health
+	// Metrics is served at /metrics unless it is nil.
+	Metrics http.Handler
+	Logger  *slog.Logger
+}
+
+// NewRouter returns the router serving every API route behind the auth
+// middleware.
+func NewRouter(deps RouterDeps) *mux.Router {
+	tenderHandler := NewTenderHandler(deps.TenderService, deps.UserService, deps.Logger)
+	bidHandler := NewBidHandler(deps.BidService, deps.Logger)
+	authHandler := NewAuthHandler(deps.AuthService, deps.Logger)
+	organizationHandler := NewOrganizationHandler(deps.OrganizationService, deps.Logger)
+	userHandler := NewUserHandler(deps.UserService, deps.Logger)
+	outboxHandler := NewOutboxHandler(deps.OutboxService, deps.Logger)
+	webhookHandler := NewWebhookHandler(deps.WebhookService, deps.Logger)
+
+	router := mux.NewRouter()
+	router.Use(auth.Middleware(deps.Tokens, deps.AllowUsername, deps.Logger))
+	router.HandleFunc("/api/ping", PingHandler).Methods("GET")
+	router.HandleFunc("/api/health/live", deps.Health.Live).Methods("GET")
+	router.HandleFunc("/api/health/ready", deps.Health.Ready).Methods("GET")
+	if deps.Metrics != nil {
+		router.Handle("/metrics", deps.Metrics).Methods("GET")
+	}
+	router.HandleFunc("/api/auth/token", authHandler.IssueToken).Methods("POST")
+	router.HandleFunc("/api/tenders", tenderHandler.GetTenders).Methods("GET")
+	router.HandleFunc("/api/tenders/new", tenderHandler.CreateTender).Methods("POST")
+	router.HandleFunc("/api/tenders/my", tenderHandler.GetUserTenders).Methods("GET")
+	router.HandleFunc("/api/tenders/search", tenderHandler.SearchTenders).Methods("GET")
+
+	router.HandleFunc("/api/tenders/{tenderId}/status", tenderHandler.GetTenderStatus).Methods("GET")
+	router.HandleFunc("/api/tenders/{tenderId}/status", tenderHandler.UpdateTenderStatus).Methods("PUT")
+	router.HandleFunc("/api/tenders/{tenderId}/edit", tenderHandler.EditTender).Methods("PATCH")
+	router.HandleFunc("/api/tenders/{tenderId}/rollback/{version}", tenderHandler.RollbackTenderVersion).Methods("POST")
+
+	router.HandleFunc("/api/bids/new", bidHandler.CreateBid).Methods("POST")
+	router.HandleFunc("/api/bids/my", bidHandler.GetUserBids).Methods("GET")
+	router.HandleFunc("/api/bids/search", bidHandler.SearchBids).Methods("GET")
+	router.HandleFunc("/api/bids/{tenderId}/list", bidHandler.GetBidsByTenderID).Methods("GET")
+	router.HandleFunc("/api/bids/{bidId}/status", bidHandler.GetBidStatus).Methods("GET")
+	router.HandleFunc("/api/bids/{bidId}/status", bidHandler.UpdateBidStatus).Methods("PUT")
+	router.HandleFunc("/api/bids/{bidId}/edit", bidHandler.EditBid).Methods("PATCH")
+	router.HandleFunc("/api/bids/{bidId}/submit_decision", bidHandler.SubmitBidDecision).Methods("PUT")
+	router.HandleFunc("/api/bids/{bidId}/feedback", bidHandler.SubmitBidFeedback).Methods("PUT")
+	router.HandleFunc("/api/bids/{bidId}/rollback/{version}", bidHandler.RollbackBid).Methods("PUT")
+	router.HandleFunc("/api/bids/{tenderId}/reviews", bidHandler.GetBidReviews).Methods("GET")
+
+	router.HandleFunc("/api/users", userHandler.GetUsers).Methods("GET")
+	router.HandleFunc("/api/users/new", userHandler.CreateUser).Methods("POST")
+	router.HandleFunc("/api/users/by-username/{employeeUsername}", userHandler.GetUserByUsername).Methods("GET")
+	router.HandleFunc("/api/users/{userId}", userHandler.GetUser).Methods("GET")
+	router.HandleFunc("/api/users/{userId}/edit", userHandler.EditUser).Methods("PATCH")
+	router.HandleFunc("/api/users/{userId}/deactivate", userHandler.DeactivateUser).Methods("POST")
+
+	router.HandleFunc("/api/organizations", organizationHandler.GetOrganizations).Methods("GET")
+	router.HandleFunc("/api/organizations/new", organizationHandler.CreateOrganization).Methods("POST")
+	router.HandleFunc("/api/organizations/my", organizationHandler.GetUserOrganizations).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}", organizationHandler.GetOrganization).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles", organizationHandler.GetResponsibles).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles", organizationHandler.AddResponsible).Methods("POST")
+	router.HandleFunc("/api/organizations/{organizationId}/responsibles/{responsibleUsername}", organizationHandler.RemoveResponsible).Methods("DELETE")
+
+	router.HandleFunc("/api/organizations/{organizationId}/webhooks", webhookHandler.GetWebhooks).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}/webhooks", webhookHandler.CreateWebhook).Methods("POST")
+	router.HandleFunc("/api/organizations/{organizationId}/webhooks/{webhookId}", webhookHandler.GetWebhook).Methods("GET")
+	router.HandleFunc("/api/organizations/{organizationId}/webhooks/{webhookId}", webhookHandler.EditWebhook).Methods("PATCH")
+	router.HandleFunc("/api/organizations/{organizationId}/webhooks/{webhookId}", webhookHandler.DeleteWebhook).Methods("DELETE")
+	router.HandleFunc("/api/organizations/{organizationId}/webhooks/{webhookId}/deliveries", webhookHandler.GetWebhookDeliveries).Methods("GET")
+
+	router.HandleFunc("/api/admin/outbox", outboxHandler.GetOutbox).Methods("GET")
+	return router
+}
@@ -3,11 +3,14 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"tender-service/internal/auth"
 	"tender-service/internal/models"
+	"tender-service/internal/repository"
 	"tender-service/internal/service"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -19,52 +22,92 @@ import (
 type TenderHandler struct {
 	tenderService service.TenderService
 	userService   service.UserService
+	logger        *slog.Logger
 }
 
-func NewTenderHandler(tenderService service.TenderService, userService service.UserService) *TenderHandler {
+func NewTenderHandler(tenderService service.TenderService, userService service.UserService, logger *slog.Logger) *TenderHandler {
 	return &TenderHandler{
 		tenderService: tenderService,
 		userService:   userService,
+		logger:        logger,
 	}
 }
 
 func (h *TenderHandler) GetTenders(w http.ResponseWriter, r *http.Request) {
-	serviceType := r.URL.Query().Get("service_type")
+	options, err := queryOptions(r, 5)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+		return
+	}
 
-	tenders, err := h.tenderService.GetTenders(serviceType)
+	tenders, err := h.tenderService.GetTenders(r.Context(), options)
 	if err != nil {
-		log.Printf("Error fetching tenders: %v", err)
+		if errors.Is(err, my_errors.ErrUnauthorized) {
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+			return
+		}
+		if errors.Is(err, my_errors.ErrBadRequest) {
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "Error fetching tenders", "error", err)
 		http.Error(w, "Error fetching tenders", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(tenders); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	writeList(w, r, h.logger, tenders, options, repository.TenderCursor)
+}
+
+// SearchTenders serves full-text search over the tenders the caller may list.
+// Results are ranked, so cursor paging is not available.
+func (h *TenderHandler) SearchTenders(w http.ResponseWriter, r *http.Request) {
+	options, err := queryOptions(r, 5)
+	if err != nil || cursorMode(r) {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+		return
+	}
+
+	results, err := h.tenderService.SearchTenders(r.Context(), r.URL.Query().Get("q"), options)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrUnauthorized) {
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+			return
+		}
+		if errors.Is(err, my_errors.ErrBadRequest) {
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "Error searching tenders", "error", err)
+		http.Error(w, "Error searching tenders", http.StatusInternalServerError)
+		return
 	}
+
+	writeJSON(w, r, h.logger, results)
 }
 
 func (h *TenderHandler) GetUserTenders(w http.ResponseWriter, r *http.Request) {
-	username := r.URL.Query().Get("username")
-
-	if username == "" {
-		http.Error(w, "Missing username", http.StatusBadRequest)
+	options, err := queryOptions(r, 5)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
 		return
 	}
 
-	tenders, err := h.tenderService.GetUserTenders(username)
+	tenders, err := h.tenderService.GetUserTenders(r.Context(), options)
 	if err != nil {
-		log.Printf("Error fetching user tenders: %v", err)
+		if errors.Is(err, my_errors.ErrUnauthorized) {
+			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+			return
+		}
+		if errors.Is(err, my_errors.ErrBadRequest) {
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request parameters")
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "Error fetching user tenders", "error", err)
 		http.Error(w, "Error fetching user tenders", http.StatusInternalServerError)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(tenders); err != nil {
-		log.Printf("Error encoding response: %v", err)
-		http.Error(w, "Error encoding response", http.StatusInternalServerError)
-	}
+	writeList(w, r, h.logger, tenders, options, repository.TenderCursor)
 }
 
 func (h *TenderHandler) CreateTender(w http.ResponseWriter, r *http.Request) {
@@ -76,36 +119,51 @@ func (h *TenderHandler) CreateTender(w http.ResponseWriter, r *http.Request) {
 		Status          string `json:"status"`
 		OrganizationID  string `json:"organizationId"`
 		CreatorUsername string `json:"creatorUsername"`
+
+		SubmissionDeadline *time.Time `json:"submissionDeadline"`
+		DecisionDeadline   *time.Time `json:"decisionDeadline"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		log.Printf("Error decoding request: %v", err)
+		h.logger.DebugContext(r.Context(), "Error decoding request body", "error", err)
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if request.CreatorUsername == "" || request.Status == "" {
+	if request.Status == "" {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing required fields")
 		return
 	}
+	// Tenders are published and closed through the status endpoint only.
+	if models.TenderStatus(request.Status) != models.Created {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "New tenders must have status CREATED")
+		return
+	}
 
 	tender := models.Tender{
 		Name:           request.Name,
 		Description:    request.Description,
 		ServiceType:    request.ServiceType,
-		Status:         models.TenderStatus(request.Status),
+		Status:         models.Created,
 		OrganizationID: request.OrganizationID,
+
+		SubmissionDeadline: request.SubmissionDeadline,
+		DecisionDeadline:   request.DecisionDeadline,
 	}
-	createdTender, err := h.tenderService.CreateTender(tender, request.CreatorUsername)
+	ctx := auth.WithLegacyUsername(r.Context(), request.CreatorUsername)
+	createdTender, err := h.tenderService.CreateTender(ctx, tender)
 	if err != nil {
 		if errors.Is(err, my_errors.ErrUnauthorized) {
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "Creator user not found")
+		} else if errors.Is(err, my_errors.ErrBadRequest) {
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Decision deadline must not precede submission deadline")
 		} else {
 			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error creating tender")
 		}
 		return
 	}
 
+	setETag(w, createdTender.Version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(createdTender)
 }
@@ -114,13 +172,7 @@ func (h *TenderHandler) GetTenderStatus(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	tenderId := vars["tenderId"]
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing username")
-		return
-	}
-
-	status, err := h.tenderService.GetTenderStatus(tenderId, username)
+	status, version, err := h.tenderService.GetTenderStatus(r.Context(), tenderId)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrTenderNotFound):
@@ -137,6 +189,7 @@ func (h *TenderHandler) GetTenderStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	setETag(w, version)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(status)
 }
@@ -146,9 +199,7 @@ func (h *TenderHandler) UpdateTenderStatus(w http.ResponseWriter, r *http.Reques
 	tenderId := vars["tenderId"]
 
 	statusStr := r.URL.Query().Get("status")
-	username := r.URL.Query().Get("username")
-
-	if statusStr == "" || username == "" {
+	if statusStr == "" {
 		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing required parameters")
 		return
 	}
@@ -159,7 +210,13 @@ func (h *TenderHandler) UpdateTenderStatus(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = h.tenderService.UpdateTenderStatus(tenderId, status, username)
+	expected, err := expectedVersion(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid expected version")
+		return
+	}
+
+	tender, err := h.tenderService.UpdateTenderStatus(r.Context(), tenderId, status, expected)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrTenderNotFound):
@@ -168,31 +225,34 @@ func (h *TenderHandler) UpdateTenderStatus(w http.ResponseWriter, r *http.Reques
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrInvalidTenderTransition):
+			utils.WriteErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, my_errors.ErrVersionConflict):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Version conflict")
 		case errors.Is(err, my_errors.ErrBadRequest):
-			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid bid ID format")
+			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid tender ID format")
 		default:
 			utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error updating tender status")
 		}
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	setETag(w, tender.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tender)
 }
 
 func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	tenderId := vars["tenderId"]
 
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing username")
-		return
-	}
-
 	var request struct {
 		Name        *string `json:"name"`
 		Description *string `json:"description"`
 		ServiceType *string `json:"serviceType"`
+
+		SubmissionDeadline *time.Time `json:"submissionDeadline"`
+		DecisionDeadline   *time.Time `json:"decisionDeadline"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -200,7 +260,13 @@ func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.tenderService.EditTender(tenderId, username, request.Name, request.Description, request.ServiceType)
+	expected, err := expectedVersion(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid expected version")
+		return
+	}
+
+	tender, err := h.tenderService.EditTender(r.Context(), tenderId, expected, request.Name, request.Description, request.ServiceType, request.SubmissionDeadline, request.DecisionDeadline)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrTenderNotFound):
@@ -209,6 +275,10 @@ func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrTenderNotEditable):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Tender cannot be edited in its current status")
+		case errors.Is(err, my_errors.ErrVersionConflict):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Version conflict")
 		case errors.Is(err, my_errors.ErrBadRequest):
 			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request data")
 		default:
@@ -217,19 +287,15 @@ func (h *TenderHandler) EditTender(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	setETag(w, tender.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tender)
 }
 
 func (h *TenderHandler) RollbackTenderVersion(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	tenderId := vars["tenderId"]
 	versionStr := vars["version"]
-	username := r.URL.Query().Get("username")
-
-	if username == "" {
-		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing required parameters")
-		return
-	}
 
 	version, err := strconv.Atoi(versionStr)
 	if err != nil || version <= 0 {
@@ -237,7 +303,13 @@ func (h *TenderHandler) RollbackTenderVersion(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	err = h.tenderService.RollbackTenderVersion(tenderId, version, username)
+	expected, err := expectedVersion(r)
+	if err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid expected version")
+		return
+	}
+
+	tender, err := h.tenderService.RollbackTenderVersion(r.Context(), tenderId, version, expected)
 	if err != nil {
 		switch {
 		case errors.Is(err, my_errors.ErrTenderNotFound):
@@ -248,6 +320,10 @@ func (h *TenderHandler) RollbackTenderVersion(w http.ResponseWriter, r *http.Req
 			utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
 		case errors.Is(err, my_errors.ErrForbidden):
 			utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+		case errors.Is(err, my_errors.ErrTenderNotEditable):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Tender cannot be edited in its current status")
+		case errors.Is(err, my_errors.ErrVersionConflict):
+			utils.WriteErrorResponse(w, http.StatusConflict, "Version conflict")
 		case errors.Is(err, my_errors.ErrBadRequest):
 			utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid tender ID or version")
 		default:
@@ -256,5 +332,7 @@ func (h *TenderHandler) RollbackTenderVersion(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	setETag(w, tender.Version)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tender)
 }
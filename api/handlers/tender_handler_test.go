@@ -2,106 +2,221 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"tender-service/internal/auth"
 	my_errors "tender-service/internal/errors"
+	"tender-service/internal/logging"
 	"tender-service/internal/models"
+	"tender-service/internal/repository"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 )
 
+// mockCaller returns how the request identified its caller: the username, or
+// the employee ID for endpoints that take one.
+func mockCaller(ctx context.Context) string {
+	identity := auth.IdentityFromContext(ctx)
+	if identity.Username != "" {
+		return identity.Username
+	}
+	return identity.UserID
+}
+
+// withLegacyAuth identifies callers by the username parameter, the way the
+// server does with AUTH_ALLOW_USERNAME on.
+func withLegacyAuth(handler http.Handler) http.Handler {
+	return auth.Middleware(nil, true, logging.Discard())(handler)
+}
+
 type MockUserService struct{}
 
-func (m *MockUserService) GetUserIDByUsername(username string) (string, error) {
+func (m *MockUserService) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	return &models.User{ID: userID}, nil
+}
+
+func (m *MockUserService) GetUserIDByUsername(ctx context.Context, username string) (string, error) {
 	return "550e8400-e29b-41d4-a716-446655440002", nil
 }
 
-func (m *MockUserService) GetUserByUsername(username string) (*models.User, error) {
+func (m *MockUserService) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
 	if username == "non-existent-user" {
 		return nil, my_errors.ErrUserNotFound
 	}
 	return &models.User{ID: "550e8400-e29b-41d4-a716-446655440002", Username: username}, nil
 }
 
-func (m *MockUserService) CheckUserPermission(userID, organizationID string) (bool, error) {
+func (m *MockUserService) CheckUserPermission(ctx context.Context, userID, organizationID string) (bool, error) {
 	if userID == "550e8400-e29b-41d4-a716-446655440002" && organizationID == "550e8400-e29b-41d4-a716-446655440020" {
 		return true, nil
 	}
 	return false, my_errors.ErrForbidden
 }
 
+func (m *MockUserService) CreateEmployee(ctx context.Context, user models.User, password string) (models.User, error) {
+	if mockCaller(ctx) == "" {
+		return models.User{}, my_errors.ErrUnauthorized
+	}
+	if user.Username == "taken-user" {
+		return models.User{}, my_errors.ErrUsernameTaken
+	}
+	user.ID = "550e8400-e29b-41d4-a716-446655440005"
+	user.Active = true
+	return user, nil
+}
+
+func (m *MockUserService) GetEmployee(ctx context.Context, userID string) (*models.User, error) {
+	if userID != "550e8400-e29b-41d4-a716-446655440002" {
+		return nil, my_errors.ErrUserNotFound
+	}
+	return &models.User{ID: userID, Username: "test-user", Active: true}, nil
+}
+
+func (m *MockUserService) GetEmployeeByUsername(ctx context.Context, username string) (*models.User, error) {
+	return m.GetUserByUsername(ctx, username)
+}
+
+func (m *MockUserService) ListEmployees(ctx context.Context, filter repository.UserFilter) ([]models.User, error) {
+	return []models.User{{ID: "550e8400-e29b-41d4-a716-446655440002", Username: filter.Search, Active: true}}, nil
+}
+
+func (m *MockUserService) UpdateEmployee(ctx context.Context, userID string, firstName, lastName *string) (*models.User, error) {
+	user := &models.User{ID: userID, Username: "test-user", Active: true}
+	if firstName != nil {
+		user.FirstName = *firstName
+	}
+	if lastName != nil {
+		user.LastName = *lastName
+	}
+	return user, nil
+}
+
+func (m *MockUserService) DeactivateEmployee(ctx context.Context, userID string) error {
+	if mockCaller(ctx) != "test-user" {
+		return my_errors.ErrForbidden
+	}
+	return nil
+}
+
 type MockTenderService struct{}
 
-func (m *MockTenderService) GetTenders(serviceType string) ([]models.Tender, error) {
-	return []models.Tender{
-		{ID: "1", Name: "Test Tender", ServiceType: "Construction"},
+func (m *MockTenderService) GetTenders(ctx context.Context, options repository.QueryOptions) ([]models.Tender, error) {
+	if mockCaller(ctx) == "non-existent-user" {
+		return nil, my_errors.ErrUnauthorized
+	}
+	var tenders []models.Tender
+	for _, serviceType := range options.ServiceTypes {
+		tenders = append(tenders, models.Tender{ID: serviceType, Name: "Test Tender", ServiceType: serviceType})
+	}
+	if len(tenders) == 0 {
+		tenders = append(tenders, models.Tender{ID: "1", Name: "Test Tender", ServiceType: "Construction"})
+	}
+	if options.Limit < len(tenders) {
+		tenders = tenders[:options.Limit]
+	}
+	return tenders, nil
+}
+
+func (m *MockTenderService) SearchTenders(ctx context.Context, query string, options repository.QueryOptions) ([]models.TenderSearchResult, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, my_errors.ErrBadRequest
+	}
+	return []models.TenderSearchResult{
+		{
+			Tender:    models.Tender{ID: "1", Name: "Road repair", ServiceType: "Construction"},
+			Rank:      0.6,
+			Highlight: "<b>Road</b> repair",
+		},
 	}, nil
 }
 
-func (m *MockTenderService) CreateTender(tender models.Tender, creatorUsername string) (models.Tender, error) {
+func (m *MockTenderService) CreateTender(ctx context.Context, tender models.Tender) (models.Tender, error) {
 	tender.ID = "21873f49-5776-4fb1-8866-aae300a08e45"
 	return tender, nil
 }
 
-func (m *MockTenderService) GetUserTenders(username string) ([]models.Tender, error) {
+func (m *MockTenderService) GetUserTenders(ctx context.Context, options repository.QueryOptions) ([]models.Tender, error) {
 	return []models.Tender{
 		{ID: "1", Name: "User Tender", CreatorID: "550e8400-e29b-41d4-a716-446655440002"},
 	}, nil
 }
 
-func (m *MockTenderService) GetTenderStatus(tenderId, username string) (models.TenderStatus, error) {
+func (m *MockTenderService) GetTenderStatus(ctx context.Context, tenderId string) (models.TenderStatus, int, error) {
 	if tenderId == "invalid-id" {
-		return "", my_errors.ErrBadRequest
+		return "", 0, my_errors.ErrBadRequest
 	}
 	if tenderId == "nonexistent-tender-id" {
-		return "", my_errors.ErrTenderNotFound
+		return "", 0, my_errors.ErrTenderNotFound
 	}
-	return models.Created, nil
+	return models.Created, mockTenderVersion, nil
 }
 
-func (m *MockTenderService) EditTender(tenderId, username string, name, description, serviceType *string) error {
+// mockTenderVersion is the version every tender served by MockTenderService
+// is currently at.
+const mockTenderVersion = 1
+
+func (m *MockTenderService) EditTender(ctx context.Context, tenderId string, expectedVersion int, name, description, serviceType *string, submissionDeadline, decisionDeadline *time.Time) (models.Tender, error) {
 	if tenderId == "invalid-id" {
-		return my_errors.ErrBadRequest
+		return models.Tender{}, my_errors.ErrBadRequest
 	}
 	if tenderId == "nonexistent-tender-id" {
-		return my_errors.ErrTenderNotFound
+		return models.Tender{}, my_errors.ErrTenderNotFound
 	}
-	return nil
+	if tenderId == "closed-tender-id" {
+		return models.Tender{}, my_errors.ErrTenderNotEditable
+	}
+	if expectedVersion != 0 && expectedVersion != mockTenderVersion {
+		return models.Tender{}, my_errors.ErrVersionConflict
+	}
+	return models.Tender{ID: tenderId, Name: *name, Status: models.Created, Version: mockTenderVersion + 1}, nil
 }
 
-func (m *MockTenderService) UpdateTenderStatus(tenderId string, status models.TenderStatus, username string) error {
+func (m *MockTenderService) UpdateTenderStatus(ctx context.Context, tenderId string, status models.TenderStatus, expectedVersion int) (models.Tender, error) {
 	if tenderId == "invalid-id" {
-		return my_errors.ErrBadRequest
+		return models.Tender{}, my_errors.ErrBadRequest
 	}
 	if tenderId == "nonexistent-tender-id" {
-		return my_errors.ErrTenderNotFound
+		return models.Tender{}, my_errors.ErrTenderNotFound
 	}
-	return nil
+	if tenderId == "closed-tender-id" {
+		return models.Tender{}, fmt.Errorf("%w: %s -> %s", my_errors.ErrInvalidTenderTransition, models.Closed, status)
+	}
+	if expectedVersion != 0 && expectedVersion != mockTenderVersion {
+		return models.Tender{}, my_errors.ErrVersionConflict
+	}
+	return models.Tender{ID: tenderId, Status: status, Version: mockTenderVersion + 1}, nil
 }
 
-func (m *MockTenderService) RollbackTenderVersion(tenderId string, version int, username string) error {
+func (m *MockTenderService) RollbackTenderVersion(ctx context.Context, tenderId string, version int, expectedVersion int) (models.Tender, error) {
 	if version == 999 {
-		return my_errors.ErrTenderHistoryNotFound
+		return models.Tender{}, my_errors.ErrTenderHistoryNotFound
 	}
 	if tenderId == "invalid-id" {
-		return my_errors.ErrBadRequest
+		return models.Tender{}, my_errors.ErrBadRequest
 	}
-	return nil
+	if expectedVersion != 0 && expectedVersion != mockTenderVersion {
+		return models.Tender{}, my_errors.ErrVersionConflict
+	}
+	return models.Tender{ID: tenderId, Status: models.Created, Version: mockTenderVersion + 1}, nil
 }
 
 func TestGetTenders(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	req, err := http.NewRequest("GET", "/api/tenders", nil)
 	assert.NoError(t, err)
 
 	rr := httptest.NewRecorder()
-	handler.GetTenders(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.GetTenders)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	var tenders []models.Tender
@@ -111,10 +226,95 @@ func TestGetTenders(t *testing.T) {
 	assert.Equal(t, "Test Tender", tenders[0].Name)
 }
 
+func TestGetTenders_UnknownUser(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/tenders?username=non-existent-user", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	withLegacyAuth(http.HandlerFunc(handler.GetTenders)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestGetTenders_MultipleServiceTypesAndLimit(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/tenders?service_type=Delivery&service_type=Construction,Manufacture&limit=2", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	withLegacyAuth(http.HandlerFunc(handler.GetTenders)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var tenders []models.Tender
+	err = json.NewDecoder(rr.Body).Decode(&tenders)
+	assert.NoError(t, err)
+	assert.Len(t, tenders, 2)
+	assert.Equal(t, "Delivery", tenders[0].ServiceType)
+	assert.Equal(t, "Construction", tenders[1].ServiceType)
+}
+
+func TestGetTenders_InvalidQueryOptions(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	for _, query := range []string{"limit=51", "offset=-1", "sort=price", "order=up", "createdFrom=yesterday"} {
+		req, err := http.NewRequest("GET", "/api/tenders?"+query, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		withLegacyAuth(http.HandlerFunc(handler.GetTenders)).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
+func TestSearchTenders(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/tenders/search?q=road", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	withLegacyAuth(http.HandlerFunc(handler.SearchTenders)).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var results []models.TenderSearchResult
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&results))
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Road repair", results[0].Name)
+	assert.Equal(t, "<b>Road</b> repair", results[0].Highlight)
+}
+
+func TestSearchTenders_InvalidQuery(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	for _, query := range []string{"", "q=", "q=road&cursor=", "q=road&limit=0"} {
+		req, err := http.NewRequest("GET", "/api/tenders/search?"+query, nil)
+		assert.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		withLegacyAuth(http.HandlerFunc(handler.SearchTenders)).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, query)
+	}
+}
+
 func TestCreateTender(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	tender := models.Tender{
 		Name:           "New Tender",
@@ -138,7 +338,7 @@ func TestCreateTender(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json")
 
 	rr := httptest.NewRecorder()
-	handler.CreateTender(rr, req)
+	withLegacyAuth(http.HandlerFunc(handler.CreateTender)).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 	var createdTender models.Tender
@@ -147,10 +347,53 @@ func TestCreateTender(t *testing.T) {
 	assert.Equal(t, "21873f49-5776-4fb1-8866-aae300a08e45", createdTender.ID)
 }
 
+func TestCreateTender_RejectsOtherStatuses(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	for _, status := range []string{"PUBLISHED", "CLOSED", "BOGUS"} {
+		body, _ := json.Marshal(map[string]interface{}{
+			"name":            "New Tender",
+			"description":     "Tender Description",
+			"serviceType":     "Construction",
+			"status":          status,
+			"organizationId":  "550e8400-e29b-41d4-a716-446655440020",
+			"creatorUsername": "user1",
+		})
+
+		req, err := http.NewRequest("POST", "/api/tenders/new", bytes.NewBuffer(body))
+		assert.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+
+		rr := httptest.NewRecorder()
+		withLegacyAuth(http.HandlerFunc(handler.CreateTender)).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusBadRequest, rr.Code, status)
+	}
+}
+
+func TestGetTenderStatus_SetsETag(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	req, err := http.NewRequest("GET", "/api/tenders/550e8400-e29b-41d4-a716-446655440000/status?username=user1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/status", handler.GetTenderStatus).Methods("GET")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"1"`, rr.Header().Get("ETag"))
+}
+
 func TestUpdateTenderStatus_InvalidTenderID(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/tenders/invalid-id/status?status=PUBLISHED&username=user1", nil)
 	assert.NoError(t, err)
@@ -158,15 +401,54 @@ func TestUpdateTenderStatus_InvalidTenderID(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/tenders/{tenderId}/status", handler.UpdateTenderStatus).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
 
+func TestUpdateTenderStatus_TransitionNotAllowed(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/tenders/closed-tender-id/status?status=CREATED&username=user1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/status", handler.UpdateTenderStatus).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+
+	var response map[string]string
+	err = json.NewDecoder(rr.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "tender status transition not allowed: CLOSED -> CREATED", response["reason"])
+}
+
+func TestEditTender_ClosedTender(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	reqBody := `{"name": "Updated Tender Name"}`
+	req, err := http.NewRequest("PATCH", "/api/tenders/closed-tender-id/edit?username=user1", bytes.NewBuffer([]byte(reqBody)))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/edit", handler.EditTender).Methods("PATCH")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
 func TestEditTender_TenderNotFound(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	reqBody := `{"name": "Updated Tender Name"}`
 	req, err := http.NewRequest("PATCH", "/api/tenders/nonexistent-tender-id/edit?username=user1", bytes.NewBuffer([]byte(reqBody)))
@@ -176,7 +458,7 @@ func TestEditTender_TenderNotFound(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/tenders/{tenderId}/edit", handler.EditTender).Methods("PATCH")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
@@ -184,7 +466,7 @@ func TestEditTender_TenderNotFound(t *testing.T) {
 func TestUpdateTenderStatus_Success(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/tenders/d3bab548-a6bf-4838-9127-b40f77ec7812/status?status=PUBLISHED&username=user1", nil)
 	assert.NoError(t, err)
@@ -192,7 +474,7 @@ func TestUpdateTenderStatus_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/tenders/{tenderId}/status", handler.UpdateTenderStatus).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
@@ -200,7 +482,7 @@ func TestUpdateTenderStatus_Success(t *testing.T) {
 func TestEditTender_Success(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	reqBody := `{"name": "Updated Tender Name", "description": "Updated description"}`
 	req, err := http.NewRequest("PATCH", "/api/tenders/d3bab548-a6bf-4838-9127-b40f77ec7812/edit?username=user1", bytes.NewBuffer([]byte(reqBody)))
@@ -210,15 +492,71 @@ func TestEditTender_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/tenders/{tenderId}/edit", handler.EditTender).Methods("PATCH")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
+}
+
+func TestEditTender_VersionConflict(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	reqBody := `{"name": "Updated Tender Name"}`
+	req, err := http.NewRequest("PATCH", "/api/tenders/d3bab548-a6bf-4838-9127-b40f77ec7812/edit?username=user1", bytes.NewBuffer([]byte(reqBody)))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"3"`)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/edit", handler.EditTender).Methods("PATCH")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestEditTender_InvalidIfMatch(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	reqBody := `{"name": "Updated Tender Name"}`
+	req, err := http.NewRequest("PATCH", "/api/tenders/d3bab548-a6bf-4838-9127-b40f77ec7812/edit?username=user1", bytes.NewBuffer([]byte(reqBody)))
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"abc"`)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/edit", handler.EditTender).Methods("PATCH")
+	withLegacyAuth(router).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestUpdateTenderStatus_ExpectedVersionMatches(t *testing.T) {
+	mockService := &MockTenderService{}
+	mockUserService := &MockUserService{}
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
+
+	req, err := http.NewRequest("PUT", "/api/tenders/d3bab548-a6bf-4838-9127-b40f77ec7812/status?status=PUBLISHED&username=user1&expectedVersion=1", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/status", handler.UpdateTenderStatus).Methods("PUT")
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `"2"`, rr.Header().Get("ETag"))
 }
 
 func TestRollbackTenderVersion_Success(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/tenders/d3bab548-a6bf-4838-9127-b40f77ec7812/rollback/2?username=user1", nil)
 	assert.NoError(t, err)
@@ -226,7 +564,7 @@ func TestRollbackTenderVersion_Success(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/tenders/{tenderId}/rollback/{version}", handler.RollbackTenderVersion).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusOK, rr.Code)
 }
@@ -234,7 +572,7 @@ func TestRollbackTenderVersion_Success(t *testing.T) {
 func TestRollbackTenderVersion_InvalidTenderID(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/tenders/invalid-id/rollback/2?username=user1", nil)
 	assert.NoError(t, err)
@@ -242,7 +580,7 @@ func TestRollbackTenderVersion_InvalidTenderID(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/tenders/{tenderId}/rollback/{version}", handler.RollbackTenderVersion).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusBadRequest, rr.Code)
 }
@@ -250,7 +588,7 @@ func TestRollbackTenderVersion_InvalidTenderID(t *testing.T) {
 func TestRollbackTenderVersion_NonexistentVersion(t *testing.T) {
 	mockService := &MockTenderService{}
 	mockUserService := &MockUserService{}
-	handler := NewTenderHandler(mockService, mockUserService)
+	handler := NewTenderHandler(mockService, mockUserService, logging.Discard())
 
 	req, err := http.NewRequest("PUT", "/api/tenders/d3bab548-a6bf-4838-9127-b40f77ec7812/rollback/999?username=user1", nil)
 	assert.NoError(t, err)
@@ -258,7 +596,7 @@ func TestRollbackTenderVersion_NonexistentVersion(t *testing.T) {
 	rr := httptest.NewRecorder()
 	router := mux.NewRouter()
 	router.HandleFunc("/api/tenders/{tenderId}/rollback/{version}", handler.RollbackTenderVersion).Methods("PUT")
-	router.ServeHTTP(rr, req)
+	withLegacyAuth(router).ServeHTTP(rr, req)
 
 	assert.Equal(t, http.StatusNotFound, rr.Code)
 }
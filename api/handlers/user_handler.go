@@ -1,51 +1,154 @@
 package handlers
 
 import (
-	"database/sql"
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+	"tender-service/internal/service"
+	"tender-service/utils"
+
+	"github.com/gorilla/mux"
+
+	my_errors "tender-service/internal/errors"
 )
 
-type Employee struct {
-	ID        string `json:"id"`
-	Username  string `json:"username"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+type UserHandler struct {
+	userService service.UserService
+	logger      *slog.Logger
 }
 
-type UserHandler struct {
-	db *sql.DB
+func NewUserHandler(userService service.UserService, logger *slog.Logger) *UserHandler {
+	return &UserHandler{userService: userService, logger: logger}
 }
 
-func NewUserHandler(db *sql.DB) *UserHandler {
-	return &UserHandler{db: db}
+// writeError maps errors shared by all employee endpoints.
+func (h *UserHandler) writeError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, my_errors.ErrUnauthorized):
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+	case errors.Is(err, my_errors.ErrForbidden):
+		utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+	case errors.Is(err, my_errors.ErrUserNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Employee not found")
+	case errors.Is(err, my_errors.ErrUsernameTaken):
+		utils.WriteErrorResponse(w, http.StatusConflict, "Username is already taken")
+	case errors.Is(err, my_errors.ErrBadRequest):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request data")
+	default:
+		h.logger.ErrorContext(r.Context(), "Error "+action, "error", err)
+		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error "+action)
+	}
 }
 
-func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	rows, err := h.db.Query("SELECT id, username, first_name, last_name, created_at, updated_at FROM employee")
+func (h *UserHandler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	users, err := h.userService.ListEmployees(r.Context(), repository.UserFilter{
+		Search:          query.Get("search"),
+		IncludeInactive: query.Get("includeInactive") == "true",
+		Limit:           limit,
+		Offset:          offset,
+	})
 	if err != nil {
-		log.Printf("Error querying users: %v", err)
-		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		h.writeError(w, r, err, "fetching employees")
 		return
 	}
-	defer rows.Close()
 
-	var users []Employee
+	writeJSON(w, r, h.logger, users)
+}
+
+func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Username  string `json:"username"`
+		FirstName string `json:"first_name"`
+		LastName  string `json:"last_name"`
+		Password  string `json:"password"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.Username == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing required fields")
+		return
+	}
+
+	user, err := h.userService.CreateEmployee(r.Context(), models.User{
+		Username:  request.Username,
+		FirstName: request.FirstName,
+		LastName:  request.LastName,
+	}, request.Password)
+	if err != nil {
+		h.writeError(w, r, err, "creating employee")
+		return
+	}
+
+	writeJSON(w, r, h.logger, user)
+}
 
-	for rows.Next() {
-		var user Employee
-		err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
-		if err != nil {
-			log.Printf("Error scanning user: %v", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
-		}
-		users = append(users, user)
+func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.userService.GetEmployee(r.Context(), mux.Vars(r)["userId"])
+	if err != nil {
+		h.writeError(w, r, err, "fetching employee")
+		return
+	}
+
+	writeJSON(w, r, h.logger, user)
+}
+
+func (h *UserHandler) GetUserByUsername(w http.ResponseWriter, r *http.Request) {
+	user, err := h.userService.GetEmployeeByUsername(r.Context(), mux.Vars(r)["employeeUsername"])
+	if err != nil {
+		h.writeError(w, r, err, "fetching employee")
+		return
+	}
+
+	writeJSON(w, r, h.logger, user)
+}
+
+func (h *UserHandler) EditUser(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		FirstName *string `json:"first_name"`
+		LastName  *string `json:"last_name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	user, err := h.userService.UpdateEmployee(r.Context(), mux.Vars(r)["userId"], request.FirstName, request.LastName)
+	if err != nil {
+		h.writeError(w, r, err, "editing employee")
+		return
+	}
+
+	writeJSON(w, r, h.logger, user)
+}
+
+func (h *UserHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	if err := h.userService.DeactivateEmployee(r.Context(), mux.Vars(r)["userId"]); err != nil {
+		h.writeError(w, r, err, "deactivating employee")
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(users)
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"tender-service/internal/logging"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func newUserRouter() http.Handler {
+	handler := NewUserHandler(&MockUserService{}, logging.Discard())
+	router := mux.NewRouter()
+	router.HandleFunc("/api/users", handler.GetUsers).Methods("GET")
+	router.HandleFunc("/api/users/new", handler.CreateUser).Methods("POST")
+	router.HandleFunc("/api/users/{userId}", handler.GetUser).Methods("GET")
+	router.HandleFunc("/api/users/{userId}/edit", handler.EditUser).Methods("PATCH")
+	router.HandleFunc("/api/users/{userId}/deactivate", handler.DeactivateUser).Methods("POST")
+	return withLegacyAuth(router)
+}
+
+func TestCreateUser(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/users/new?username=test-user", bytes.NewBufferString(`{"username":"new-user","first_name":"New"}`))
+	rr := httptest.NewRecorder()
+	newUserRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"username":"new-user"`)
+}
+
+func TestCreateUser_UsernameTaken(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/users/new?username=test-user", bytes.NewBufferString(`{"username":"taken-user"}`))
+	rr := httptest.NewRecorder()
+	newUserRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
+
+func TestCreateUser_MissingUsername(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/users/new?username=test-user", bytes.NewBufferString(`{"first_name":"New"}`))
+	rr := httptest.NewRecorder()
+	newUserRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetUsers_PassesSearch(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/users?username=test-user&search=ann&limit=5", nil)
+	rr := httptest.NewRecorder()
+	newUserRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"username":"ann"`)
+}
+
+func TestGetUser_NotFound(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/users/550e8400-e29b-41d4-a716-446655440099?username=test-user", nil)
+	rr := httptest.NewRecorder()
+	newUserRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestEditUser(t *testing.T) {
+	req := httptest.NewRequest("PATCH", "/api/users/550e8400-e29b-41d4-a716-446655440002/edit?username=test-user", bytes.NewBufferString(`{"last_name":"Smith"}`))
+	rr := httptest.NewRecorder()
+	newUserRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"last_name":"Smith"`)
+}
+
+func TestDeactivateUser_Forbidden(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/users/550e8400-e29b-41d4-a716-446655440002/deactivate?username=outsider", nil)
+	rr := httptest.NewRecorder()
+	newUserRouter().ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
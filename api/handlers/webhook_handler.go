@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"tender-service/internal/service"
+	"tender-service/utils"
+
+	"github.com/gorilla/mux"
+
+	my_errors "tender-service/internal/errors"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	logger         *slog.Logger
+}
+
+func NewWebhookHandler(webhookService service.WebhookService, logger *slog.Logger) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService, logger: logger}
+}
+
+// writeError maps errors shared by all webhook endpoints.
+func (h *WebhookHandler) writeError(w http.ResponseWriter, r *http.Request, err error, action string) {
+	switch {
+	case errors.Is(err, my_errors.ErrUnauthorized):
+		utils.WriteErrorResponse(w, http.StatusUnauthorized, "User not found")
+	case errors.Is(err, my_errors.ErrForbidden):
+		utils.WriteErrorResponse(w, http.StatusForbidden, "Insufficient permissions")
+	case errors.Is(err, my_errors.ErrOrganizationNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Organization not found")
+	case errors.Is(err, my_errors.ErrWebhookNotFound):
+		utils.WriteErrorResponse(w, http.StatusNotFound, "Webhook not found")
+	case errors.Is(err, my_errors.ErrBadRequest):
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request data")
+	default:
+		h.logger.ErrorContext(r.Context(), "Error "+action, "error", err)
+		utils.WriteErrorResponse(w, http.StatusInternalServerError, "Error "+action)
+	}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		URL        string   `json:"url"`
+		Secret     string   `json:"secret"`
+		EventTypes []string `json:"eventTypes"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if request.URL == "" {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Missing required fields")
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(r.Context(), mux.Vars(r)["organizationId"], request.URL, request.Secret, request.EventTypes)
+	if err != nil {
+		h.writeError(w, r, err, "creating webhook")
+		return
+	}
+
+	writeJSON(w, r, h.logger, webhook)
+}
+
+func (h *WebhookHandler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhookService.GetWebhooks(r.Context(), mux.Vars(r)["organizationId"])
+	if err != nil {
+		h.writeError(w, r, err, "fetching webhooks")
+		return
+	}
+
+	writeJSON(w, r, h.logger, webhooks)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	webhook, err := h.webhookService.GetWebhook(r.Context(), vars["organizationId"], vars["webhookId"])
+	if err != nil {
+		h.writeError(w, r, err, "fetching webhook")
+		return
+	}
+
+	writeJSON(w, r, h.logger, webhook)
+}
+
+func (h *WebhookHandler) EditWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var request struct {
+		URL        *string   `json:"url"`
+		Secret     *string   `json:"secret"`
+		EventTypes *[]string `json:"eventTypes"`
+		Active     *bool     `json:"active"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		utils.WriteErrorResponse(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(r.Context(), vars["organizationId"], vars["webhookId"], request.URL, request.Secret, request.EventTypes, request.Active)
+	if err != nil {
+		h.writeError(w, r, err, "editing webhook")
+		return
+	}
+
+	writeJSON(w, r, h.logger, webhook)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := h.webhookService.DeleteWebhook(r.Context(), vars["organizationId"], vars["webhookId"]); err != nil {
+		h.writeError(w, r, err, "deleting webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	query := r.URL.Query()
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+	offset, err := strconv.Atoi(query.Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	deliveries, err := h.webhookService.GetWebhookDeliveries(r.Context(), vars["organizationId"], vars["webhookId"], limit, offset)
+	if err != nil {
+		h.writeError(w, r, err, "fetching webhook deliveries")
+		return
+	}
+
+	writeJSON(w, r, h.logger, deliveries)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"tender-service/config"
+	"tender-service/internal/auth"
+	"tender-service/internal/repository"
+
+	_ "github.com/lib/pq"
+)
+
+const usage = "usage: passwd <username> (the password is read from stdin)"
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal(usage)
+	}
+	username := os.Args[1]
+
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		log.Fatalf("Failed to read password: %v", err)
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		log.Fatal("Password must not be empty")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := sql.Open("postgres", cfg.PostgresDSN())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(db, cfg.DBQueryTimeout)
+	user, err := userRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		log.Fatalf("Failed to find employee %s: %v", username, err)
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		log.Fatalf("Failed to hash password: %v", err)
+	}
+	if err := userRepo.SetPasswordHash(ctx, user.ID, hash); err != nil {
+		log.Fatalf("Failed to set password: %v", err)
+	}
+	fmt.Printf("password set for %s\n", username)
+}
@@ -1,61 +1,191 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"crypto/rand"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"tender-service/api/handlers"
 	"tender-service/config"
+	"tender-service/internal/auth"
+	"tender-service/internal/event"
+	"tender-service/internal/logging"
+	"tender-service/internal/metrics"
+	"tender-service/internal/models"
+	"tender-service/internal/outbox"
 	"tender-service/internal/repository"
+	"tender-service/internal/repository/memory"
+	"tender-service/internal/scheduler"
+	"tender-service/internal/server"
 	"tender-service/internal/service"
+	"tender-service/internal/webhook"
+	"tender-service/migrations"
 
 	"database/sql"
 
-	"github.com/gorilla/mux"
 	_ "github.com/lib/pq"
 )
 
 func main() {
-	cfg := config.LoadConfig()
-
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=require",
-		cfg.PostgresHost, cfg.PostgresPort, cfg.PostgresUser, cfg.PostgresPassword, cfg.PostgresDB)
-	db, err := sql.Open("postgres", connStr)
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-	defer db.Close()
-
-	tenderRepo := repository.NewTenderRepository(db)
-	userRepo := repository.NewUserRepository(db)
-	bidRepo := repository.NewBidRepository(db)
-
-	userService := service.NewUserService(userRepo)
-	tenderService := service.NewTenderService(tenderRepo, userService)
-	bidService := service.NewBidService(bidRepo, tenderRepo, userRepo)
-
-	tenderHandler := handlers.NewTenderHandler(tenderService, userService)
-	bidHandler := handlers.NewBidHandler(bidService)
-
-	router := mux.NewRouter()
-	router.HandleFunc("/api/ping", handlers.PingHandler).Methods("GET")
-	router.HandleFunc("/api/tenders", tenderHandler.GetTenders).Methods("GET")
-	router.HandleFunc("/api/tenders/new", tenderHandler.CreateTender).Methods("POST")
-	router.HandleFunc("/api/tenders/my", tenderHandler.GetUserTenders).Methods("GET")
-
-	router.HandleFunc("/api/tenders/{tenderId}/status", tenderHandler.GetTenderStatus).Methods("GET")
-	router.HandleFunc("/api/tenders/{tenderId}/status", tenderHandler.UpdateTenderStatus).Methods("PUT")
-	router.HandleFunc("/api/tenders/{tenderId}/edit", tenderHandler.EditTender).Methods("PATCH")
-	router.HandleFunc("/api/tenders/{tenderId}/rollback/{version}", tenderHandler.RollbackTenderVersion).Methods("POST")
-
-	router.HandleFunc("/api/bids/new", bidHandler.CreateBid).Methods("POST")
-	router.HandleFunc("/api/bids/my", bidHandler.GetUserBids).Methods("GET")
-	router.HandleFunc("/api/bids/{tenderId}/list", bidHandler.GetBidsByTenderID).Methods("GET")
-	router.HandleFunc("/api/bids/{bidId}/status", bidHandler.GetBidStatus).Methods("GET")
-	router.HandleFunc("/api/bids/{bidId}/status", bidHandler.UpdateBidStatus).Methods("PUT")
-	router.HandleFunc("/api/bids/{bidId}/edit", bidHandler.EditBid).Methods("PATCH")
-	router.HandleFunc("/api/bids/{bidId}/feedback", bidHandler.SubmitBidFeedback).Methods("PUT")
-
-	log.Printf("Server running at %s", cfg.ServerAddress)
-	log.Fatal(http.ListenAndServe(cfg.ServerAddress, router))
+		log.Fatal(err)
+	}
+	logger := logging.New(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	// Route the standard log package, still used by the server package and
+	// for fatal errors, through the same handler.
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	health := server.NewHealth()
+	registry := metrics.NewRegistry()
+	registry.MustRegister(repository.QueryDuration)
+
+	var (
+		tenderRepo       repository.TenderRepository
+		userRepo         repository.UserRepository
+		bidRepo          repository.BidRepository
+		organizationRepo repository.OrganizationRepository
+		outboxRepo       repository.OutboxRepository
+		webhookRepo      repository.WebhookRepository
+		unitOfWork       repository.UnitOfWork
+	)
+
+	switch cfg.Storage {
+	case "memory":
+		store := memory.NewStore()
+		if cfg.MemorySeedFile != "" {
+			if err := store.LoadSeed(cfg.MemorySeedFile); err != nil {
+				log.Fatalf("Failed to load memory seed: %v", err)
+			}
+		}
+		logger.Info("Using in-memory storage")
+
+		tenderRepo = memory.NewTenderRepository(store)
+		userRepo = memory.NewUserRepository(store)
+		bidRepo = memory.NewBidRepository(store)
+		organizationRepo = memory.NewOrganizationRepository(store)
+		outboxRepo = memory.NewOutboxRepository(store)
+		webhookRepo = memory.NewWebhookRepository(store)
+		unitOfWork = memory.NewUnitOfWork(store)
+	case "postgres":
+		db, err := sql.Open("postgres", cfg.PostgresDSN())
+		if err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+		db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+		db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+		db.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
+		if err := server.WaitForDB(ctx, db, cfg.DBConnectAttempts); err != nil {
+			log.Fatalf("Failed to connect to database: %v", err)
+		}
+		health.AddCheck("database", server.PingDB(db))
+		metrics.RegisterDBStats(registry, db)
+
+		if cfg.MigrateOnStart {
+			migrator, err := migrations.NewMigrator(db)
+			if err != nil {
+				log.Fatalf("Failed to load migrations: %v", err)
+			}
+			applied, err := migrator.Up(ctx)
+			if err != nil {
+				log.Fatalf("Failed to apply migrations: %v", err)
+			}
+			logger.Info("Applied pending migrations", "count", len(applied))
+		}
+
+		tenderRepo = repository.NewTenderRepository(db, cfg.DBQueryTimeout)
+		userRepo = repository.NewUserRepository(db, cfg.DBQueryTimeout)
+		bidRepo = repository.NewBidRepository(db, cfg.DBQueryTimeout)
+		organizationRepo = repository.NewOrganizationRepository(db, cfg.DBQueryTimeout)
+		outboxRepo = repository.NewOutboxRepository(db, cfg.DBQueryTimeout)
+		webhookRepo = repository.NewWebhookRepository(db, cfg.DBQueryTimeout)
+		unitOfWork = repository.NewUnitOfWork(db, cfg.DBQueryTimeout)
+	default:
+		log.Fatalf("Unknown STORAGE %q, expected postgres or memory", cfg.Storage)
+	}
+
+	events := event.NewBus()
+	events.Subscribe(event.LogSubscriber(logger))
+
+	relay := outbox.NewRelay(outboxRepo, cfg.OutboxPollInterval, logger)
+	relay.Register(outbox.EventSink(events))
+	relay.Register(webhook.NewFanout(webhookRepo, tenderRepo))
+
+	unitOfWork = repository.OnCommit(unitOfWork, metrics.EventCounter(registry))
+
+	userService := service.NewUserService(userRepo, organizationRepo, unitOfWork, cfg.AdminUsernames, logger)
+	tenderTransitions := models.NewTenderStateMachine()
+	tenderTransitions.AddGuard(models.Created, models.Published, models.RequireTenderDescription)
+	tenderTransitions.AddGuard(models.Published, models.Closed, service.NoPendingBids)
+
+	tenderService := service.NewTenderService(tenderRepo, userService, tenderTransitions, unitOfWork, logger)
+	bidService := service.NewBidService(bidRepo, tenderRepo, userRepo, unitOfWork, logger)
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo, logger)
+	outboxService := service.NewOutboxService(outboxRepo, userRepo, cfg.AdminUsernames)
+	webhookService := service.NewWebhookService(webhookRepo, organizationRepo, userRepo, logger)
+
+	tokenKey := []byte(cfg.AuthTokenKey)
+	if len(tokenKey) == 0 {
+		tokenKey = make([]byte, 32)
+		if _, err := rand.Read(tokenKey); err != nil {
+			log.Fatalf("Failed to generate token key: %v", err)
+		}
+		logger.Warn("AUTH_TOKEN_KEY is not set, issued tokens will not survive a restart")
+	}
+	if len(cfg.AdminUsernames) == 0 {
+		logger.Warn("ADMIN_USERNAMES is not set, admin endpoints will refuse every employee")
+	}
+	if cfg.AuthAllowUsername {
+		logger.Warn("AUTH_ALLOW_USERNAME is on, requests without a token may identify themselves by username")
+	}
+	tokens := auth.NewTokenManager(tokenKey, cfg.AuthTokenTTL)
+	authService := service.NewAuthService(userRepo, tokens, logger)
+
+	var metricsHandler http.Handler
+	if cfg.MetricsEnabled {
+		metricsHandler = registry
+	}
+	router := handlers.NewRouter(handlers.RouterDeps{
+		TenderService:       tenderService,
+		BidService:          bidService,
+		UserService:         userService,
+		AuthService:         authService,
+		OrganizationService: organizationService,
+		OutboxService:       outboxService,
+		WebhookService:      webhookService,
+		Tokens:              tokens,
+		AllowUsername:       cfg.AuthAllowUsername,
+		Health:              health,
+		Metrics:             metricsHandler,
+		Logger:              logger,
+	})
+
+	srv := server.New(server.Config{
+		Address:         cfg.ServerAddress,
+		ReadTimeout:     cfg.ServerReadTimeout,
+		WriteTimeout:    cfg.ServerWriteTimeout,
+		IdleTimeout:     cfg.ServerIdleTimeout,
+		ShutdownTimeout: cfg.ServerShutdownTimeout,
+	}, logging.Middleware(logger)(metrics.HTTPHandler(registry, router)), health)
+	srv.Go("outbox relay", relay.Run)
+	srv.Go("webhook dispatcher", webhook.NewDispatcher(webhookRepo, nil, cfg.WebhookPollInterval, logger).Run)
+	if cfg.DeadlineCheckInterval > 0 {
+		srv.Go("deadline scheduler", scheduler.NewDeadlineScheduler(unitOfWork, cfg.DeadlineCheckInterval, logger).Run)
+	} else {
+		logger.Warn("DEADLINE_CHECK_INTERVAL is not positive, expired tenders will not be closed automatically")
+	}
+
+	if err := srv.Run(ctx); err != nil {
+		log.Fatalf("Server failed: %v", err)
+	}
+	logger.Info("Server stopped")
 }
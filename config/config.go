@@ -1,33 +1,181 @@
 package config
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
 	"os"
+	"strings"
+	"tender-service/internal/logging"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	ServerAddress    string
+	ServerAddress  string
+	Storage        string
+	MemorySeedFile string
+
+	// PostgresURL is the full connection URL given by POSTGRES_CONN or
+	// POSTGRES_JDBC_URL. It takes precedence over the individual Postgres
+	// settings, which only fill in what it leaves out.
+	PostgresURL      string
 	PostgresHost     string
 	PostgresPort     string
 	PostgresUser     string
 	PostgresPassword string
 	PostgresDB       string
+	PostgresSSLMode  string
+
+	LogFormat logging.Format
+	LogLevel  slog.Level
+
+	DBQueryTimeout    time.Duration
+	DBConnectAttempts int
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
+	DBConnMaxIdleTime time.Duration
+
+	ServerReadTimeout     time.Duration
+	ServerWriteTimeout    time.Duration
+	ServerIdleTimeout     time.Duration
+	ServerShutdownTimeout time.Duration
+
+	AuthTokenKey string
+	AuthTokenTTL time.Duration
+
+	DeadlineCheckInterval time.Duration
+	OutboxPollInterval    time.Duration
+	WebhookPollInterval   time.Duration
+
+	AdminUsernames []string
+
+	// Feature flags.
+	MigrateOnStart    bool
+	AuthAllowUsername bool
+	MetricsEnabled    bool
+}
+
+// Load reads the configuration. Every setting is taken from the first of
+// these that has it: the environment, the .env file in the working directory,
+// the YAML file named by CONFIG_FILE and the default. Both files are
+// optional. When settings are invalid, Load reports all of them at once.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("loading .env: %w", err)
+	}
+
+	var file map[string]string
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		var err error
+		if file, err = readFile(path); err != nil {
+			return nil, err
+		}
+	}
+	return load(os.LookupEnv, file)
+}
+
+func load(env func(string) (string, bool), file map[string]string) (*Config, error) {
+	s := &settings{env: env, file: file, known: make(map[string]bool)}
+
+	cfg := &Config{
+		ServerAddress:  s.string("0.0.0.0:8080", "SERVER_ADDRESS"),
+		Storage:        s.oneOf("postgres", []string{"postgres", "memory"}, "STORAGE"),
+		MemorySeedFile: s.string("", "MEMORY_SEED_FILE"),
+
+		PostgresHost:     s.string("", "POSTGRES_HOST"),
+		PostgresPort:     s.string("5432", "POSTGRES_PORT"),
+		PostgresUser:     s.string("", "POSTGRES_USERNAME", "POSTGRES_USER"),
+		PostgresPassword: s.string("", "POSTGRES_PASSWORD"),
+		PostgresDB:       s.string("", "POSTGRES_DATABASE", "POSTGRES_DB"),
+		// lib/pq supports no other modes.
+		PostgresSSLMode: s.oneOf("require", []string{"disable", "require", "verify-ca", "verify-full"}, "POSTGRES_SSLMODE"),
+
+		DBQueryTimeout:    s.duration(5*time.Second, "DB_QUERY_TIMEOUT"),
+		DBConnectAttempts: s.positiveInt(10, "DB_CONNECT_ATTEMPTS"),
+		DBMaxOpenConns:    s.int(25, "DB_MAX_OPEN_CONNS"),
+		DBMaxIdleConns:    s.int(5, "DB_MAX_IDLE_CONNS"),
+		DBConnMaxLifetime: s.duration(30*time.Minute, "DB_CONN_MAX_LIFETIME"),
+		DBConnMaxIdleTime: s.duration(5*time.Minute, "DB_CONN_MAX_IDLE_TIME"),
+
+		ServerReadTimeout:     s.duration(15*time.Second, "SERVER_READ_TIMEOUT"),
+		ServerWriteTimeout:    s.duration(30*time.Second, "SERVER_WRITE_TIMEOUT"),
+		ServerIdleTimeout:     s.duration(2*time.Minute, "SERVER_IDLE_TIMEOUT"),
+		ServerShutdownTimeout: s.duration(30*time.Second, "SERVER_SHUTDOWN_TIMEOUT"),
+
+		AuthTokenKey: s.string("", "AUTH_TOKEN_KEY"),
+		AuthTokenTTL: s.positiveDuration(time.Hour, "AUTH_TOKEN_TTL"),
+
+		DeadlineCheckInterval: s.duration(time.Minute, "DEADLINE_CHECK_INTERVAL"),
+		OutboxPollInterval:    s.positiveDuration(time.Second, "OUTBOX_POLL_INTERVAL"),
+		WebhookPollInterval:   s.positiveDuration(time.Second, "WEBHOOK_POLL_INTERVAL"),
+
+		AdminUsernames: s.list("ADMIN_USERNAMES"),
+
+		MigrateOnStart:    s.bool(false, "MIGRATE_ON_START"),
+		AuthAllowUsername: s.bool(false, "AUTH_ALLOW_USERNAME"),
+		MetricsEnabled:    s.bool(true, "METRICS_ENABLED"),
+	}
+
+	if format, err := logging.ParseFormat(s.string("", "LOG_FORMAT")); err != nil {
+		s.problem("LOG_FORMAT: %v", err)
+	} else {
+		cfg.LogFormat = format
+	}
+	if level, err := logging.ParseLevel(s.string("", "LOG_LEVEL")); err != nil {
+		s.problem("LOG_LEVEL: %v", err)
+	} else {
+		cfg.LogLevel = level
+	}
+
+	if conn := s.string("", "POSTGRES_CONN"); conn != "" {
+		cfg.PostgresURL = s.postgresURL("POSTGRES_CONN", conn)
+	} else if jdbc := s.string("", "POSTGRES_JDBC_URL"); jdbc != "" {
+		if rest, ok := strings.CutPrefix(jdbc, "jdbc:"); ok {
+			cfg.PostgresURL = s.postgresURL("POSTGRES_JDBC_URL", rest)
+		} else {
+			s.problem("POSTGRES_JDBC_URL: %q does not start with jdbc:", jdbc)
+		}
+	}
+	if cfg.Storage == "postgres" && cfg.PostgresURL == "" && cfg.PostgresHost == "" {
+		s.problem("POSTGRES_HOST: not set, and neither is POSTGRES_CONN or POSTGRES_JDBC_URL")
+	}
+	if cfg.DBMaxOpenConns > 0 && cfg.DBMaxIdleConns > cfg.DBMaxOpenConns {
+		s.problem("DB_MAX_IDLE_CONNS: %d exceeds DB_MAX_OPEN_CONNS %d", cfg.DBMaxIdleConns, cfg.DBMaxOpenConns)
+	}
+	s.checkFile()
+
+	if len(s.problems) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(s.problems...))
+	}
+	return cfg, nil
 }
 
-func LoadConfig() *Config {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+// PostgresDSN is the connection URL for lib/pq. Settings missing from
+// PostgresURL, such as the credentials that JDBC URLs usually leave out, are
+// filled in from the individual settings.
+func (c *Config) PostgresDSN() string {
+	dsn := &url.URL{Scheme: "postgres", Host: net.JoinHostPort(c.PostgresHost, c.PostgresPort), Path: "/" + c.PostgresDB}
+	if c.PostgresURL != "" {
+		// Load has already parsed it.
+		dsn, _ = url.Parse(c.PostgresURL)
 	}
 
-	return &Config{
-		ServerAddress:    os.Getenv("SERVER_ADDRESS"),
-		PostgresHost:     os.Getenv("POSTGRES_HOST"),
-		PostgresPort:     os.Getenv("POSTGRES_PORT"),
-		PostgresUser:     os.Getenv("POSTGRES_USER"),
-		PostgresPassword: os.Getenv("POSTGRES_PASSWORD"),
-		PostgresDB:       os.Getenv("POSTGRES_DB"),
+	query := dsn.Query()
+	if dsn.User == nil && !query.Has("user") && c.PostgresUser != "" {
+		if c.PostgresPassword != "" {
+			dsn.User = url.UserPassword(c.PostgresUser, c.PostgresPassword)
+		} else {
+			dsn.User = url.User(c.PostgresUser)
+		}
+	}
+	if !query.Has("sslmode") {
+		query.Set("sslmode", c.PostgresSSLMode)
 	}
+	dsn.RawQuery = query.Encode()
+	return dsn.String()
 }
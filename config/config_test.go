@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func envOf(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := load(envOf(map[string]string{"POSTGRES_HOST": "db"}), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "0.0.0.0:8080", cfg.ServerAddress)
+	assert.Equal(t, "postgres", cfg.Storage)
+	assert.Equal(t, 5*time.Second, cfg.DBQueryTimeout)
+	assert.Equal(t, 25, cfg.DBMaxOpenConns)
+	assert.True(t, cfg.MetricsEnabled)
+	assert.False(t, cfg.AuthAllowUsername)
+	assert.Equal(t, "postgres://db:5432/?sslmode=require", cfg.PostgresDSN())
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	_, err := load(envOf(map[string]string{
+		"STORAGE":              "mongo",
+		"DB_QUERY_TIMEOUT":     "soon",
+		"OUTBOX_POLL_INTERVAL": "0s",
+		"METRICS_ENABLED":      "maybe",
+		"LOG_LEVEL":            "loud",
+		"POSTGRES_CONN":        "mysql://db/app",
+	}), nil)
+	require.Error(t, err)
+
+	for _, name := range []string{"STORAGE", "DB_QUERY_TIMEOUT", "OUTBOX_POLL_INTERVAL", "METRICS_ENABLED", "LOG_LEVEL", "POSTGRES_CONN"} {
+		assert.Contains(t, err.Error(), name+":")
+	}
+}
+
+func TestLoad_ConnectionURLs(t *testing.T) {
+	cfg, err := load(envOf(map[string]string{
+		"POSTGRES_CONN":     "postgres://app:secret@db:5432/tenders",
+		"POSTGRES_USERNAME": "ignored",
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://app:secret@db:5432/tenders?sslmode=require", cfg.PostgresDSN())
+
+	cfg, err = load(envOf(map[string]string{
+		"POSTGRES_JDBC_URL": "jdbc:postgresql://db:6432/tenders?sslmode=disable",
+		"POSTGRES_USERNAME": "app",
+		"POSTGRES_PASSWORD": "p@ss word",
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "postgresql://app:p%40ss%20word@db:6432/tenders?sslmode=disable", cfg.PostgresDSN())
+}
+
+func TestLoad_FileIsLayeredUnderEnvironment(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+server:
+  address: 127.0.0.1:9000
+  read_timeout: 5s
+postgres:
+  host: file-db
+admin_usernames: [alice, bob]
+metrics_enabled: false
+`), 0o600))
+	file, err := readFile(path)
+	require.NoError(t, err)
+
+	cfg, err := load(envOf(map[string]string{"POSTGRES_HOST": "env-db"}), file)
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:9000", cfg.ServerAddress)
+	assert.Equal(t, 5*time.Second, cfg.ServerReadTimeout)
+	assert.Equal(t, "env-db", cfg.PostgresHost)
+	assert.Equal(t, []string{"alice", "bob"}, cfg.AdminUsernames)
+	assert.False(t, cfg.MetricsEnabled)
+
+	_, err = load(envOf(nil), map[string]string{"POSTGRES_HOST": "db", "SERVER_ADRESS": ":80"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SERVER_ADRESS: unknown setting")
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// readFile reads a YAML config file into settings named like the environment
+// variables. Nested keys are joined with underscores and upper-cased, so
+//
+//	server:
+//	  address: 0.0.0.0:8080
+//
+// sets SERVER_ADDRESS, as does a top-level server_address key. Lists become
+// comma-separated values.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CONFIG_FILE: %w", err)
+	}
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing CONFIG_FILE %s: %w", path, err)
+	}
+
+	file := make(map[string]string)
+	if err := flatten("", root, file); err != nil {
+		return nil, fmt.Errorf("parsing CONFIG_FILE %s: %w", path, err)
+	}
+	return file, nil
+}
+
+func flatten(prefix string, node map[string]interface{}, file map[string]string) error {
+	for key, value := range node {
+		name := strings.ToUpper(key)
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if err := flatten(name, value, file); err != nil {
+				return err
+			}
+		case []interface{}:
+			items := make([]string, len(value))
+			for i, item := range value {
+				if !scalar(item) {
+					return fmt.Errorf("%s: list items must be plain values", name)
+				}
+				items[i] = fmt.Sprint(item)
+			}
+			file[name] = strings.Join(items, ",")
+		case nil:
+			file[name] = ""
+		default:
+			if !scalar(value) {
+				return fmt.Errorf("%s: unsupported value", name)
+			}
+			file[name] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+func scalar(value interface{}) bool {
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return true
+	default:
+		return false
+	}
+}
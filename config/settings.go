@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// settings looks settings up in the environment, then in the config file,
+// and collects the problems with their values instead of stopping at the
+// first one.
+type settings struct {
+	env  func(string) (string, bool)
+	file map[string]string
+	// known holds every setting name looked up, to catch misspelt keys in
+	// the config file.
+	known    map[string]bool
+	problems []error
+}
+
+func (s *settings) problem(format string, args ...interface{}) {
+	s.problems = append(s.problems, fmt.Errorf(format, args...))
+}
+
+// lookup returns the first of names that is set, preferring the environment
+// over the file, and reports which one it was. Empty values count as unset.
+func (s *settings) lookup(names ...string) (name, value string, ok bool) {
+	for _, name := range names {
+		s.known[name] = true
+	}
+	for _, name := range names {
+		if value, ok := s.env(name); ok && value != "" {
+			return name, value, true
+		}
+	}
+	for _, name := range names {
+		if value := s.file[name]; value != "" {
+			return name, value, true
+		}
+	}
+	return names[0], "", false
+}
+
+func (s *settings) string(fallback string, names ...string) string {
+	if _, value, ok := s.lookup(names...); ok {
+		return value
+	}
+	return fallback
+}
+
+func (s *settings) oneOf(fallback string, allowed []string, names ...string) string {
+	name, value, ok := s.lookup(names...)
+	if !ok {
+		return fallback
+	}
+	for _, candidate := range allowed {
+		if value == candidate {
+			return value
+		}
+	}
+	s.problem("%s: invalid value %q, expected one of %s", name, value, strings.Join(allowed, ", "))
+	return fallback
+}
+
+func (s *settings) bool(fallback bool, names ...string) bool {
+	name, value, ok := s.lookup(names...)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		s.problem("%s: invalid value %q, expected true or false", name, value)
+		return fallback
+	}
+	return parsed
+}
+
+// int reads a non-negative number, where 0 usually means no limit.
+func (s *settings) int(fallback int, names ...string) int {
+	name, value, ok := s.lookup(names...)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		s.problem("%s: invalid value %q, expected a non-negative number", name, value)
+		return fallback
+	}
+	return parsed
+}
+
+func (s *settings) positiveInt(fallback int, names ...string) int {
+	name, value, ok := s.lookup(names...)
+	if !ok {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		s.problem("%s: invalid value %q, expected a positive number", name, value)
+		return fallback
+	}
+	return parsed
+}
+
+// duration reads a non-negative Go duration, where 0 usually means no limit.
+func (s *settings) duration(fallback time.Duration, names ...string) time.Duration {
+	name, value, ok := s.lookup(names...)
+	if !ok {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		s.problem("%s: invalid value %q, expected a non-negative duration such as 30s", name, value)
+		return fallback
+	}
+	return parsed
+}
+
+func (s *settings) positiveDuration(fallback time.Duration, names ...string) time.Duration {
+	name, value, ok := s.lookup(names...)
+	if !ok {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		s.problem("%s: invalid value %q, expected a positive duration such as 30s", name, value)
+		return fallback
+	}
+	return parsed
+}
+
+// list reads comma-separated values, skipping empty ones.
+func (s *settings) list(names ...string) []string {
+	_, value, _ := s.lookup(names...)
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// postgresURL checks that value is a postgres:// or postgresql:// URL with a
+// host.
+func (s *settings) postgresURL(name, value string) string {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		s.problem("%s: invalid URL: %v", name, err)
+		return ""
+	}
+	if parsed.Scheme != "postgres" && parsed.Scheme != "postgresql" {
+		s.problem("%s: unsupported scheme %q, expected postgres or postgresql", name, parsed.Scheme)
+		return ""
+	}
+	if parsed.Host == "" {
+		s.problem("%s: URL has no host", name)
+		return ""
+	}
+	return value
+}
+
+// checkFile reports config file keys that no setting uses.
+func (s *settings) checkFile() {
+	var unknown []string
+	for name := range s.file {
+		if !s.known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		s.problem("%s: unknown setting in CONFIG_FILE", name)
+	}
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+)
+
+// Identity is who a request says it comes from. Authenticated identities come
+// from a verified bearer token; the others are legacy username or user ID
+// claims that are only accepted while the compatibility mode is on.
+type Identity struct {
+	UserID        string
+	Username      string
+	Authenticated bool
+}
+
+// Anonymous reports whether the request carries no identity at all.
+func (i Identity) Anonymous() bool {
+	return i.UserID == "" && i.Username == ""
+}
+
+type contextKey int
+
+const (
+	identityKey contextKey = iota
+	legacyKey
+)
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey, identity)
+}
+
+func IdentityFromContext(ctx context.Context) Identity {
+	identity, _ := ctx.Value(identityKey).(Identity)
+	return identity
+}
+
+// WithLegacyUsername records a username taken from the request itself, for
+// endpoints that used to identify the caller through their body. It does
+// nothing unless the compatibility mode is on and the request is anonymous.
+func WithLegacyUsername(ctx context.Context, username string) context.Context {
+	if username == "" || !legacyAllowed(ctx) || !IdentityFromContext(ctx).Anonymous() {
+		return ctx
+	}
+	return WithIdentity(ctx, Identity{Username: username})
+}
+
+// WithLegacyUserID is WithLegacyUsername for endpoints that used to take the
+// caller's employee ID.
+func WithLegacyUserID(ctx context.Context, userID string) context.Context {
+	if userID == "" || !legacyAllowed(ctx) || !IdentityFromContext(ctx).Anonymous() {
+		return ctx
+	}
+	return WithIdentity(ctx, Identity{UserID: userID})
+}
+
+func withLegacyAllowed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, legacyKey, true)
+}
+
+func legacyAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(legacyKey).(bool)
+	return allowed
+}
+
+// UserLookup is the part of the employee storage needed to resolve callers.
+type UserLookup interface {
+	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+}
+
+// Caller resolves the request's identity to an employee. It returns
+// ErrUnauthorized for anonymous requests and ErrUserNotFound when the
+// identity does not match an employee.
+func Caller(ctx context.Context, users UserLookup) (*models.User, error) {
+	identity := IdentityFromContext(ctx)
+	switch {
+	case identity.UserID != "":
+		return users.GetUserByID(ctx, identity.UserID)
+	case identity.Username != "":
+		return users.GetUserByUsername(ctx, identity.Username)
+	default:
+		return nil, my_errors.ErrUnauthorized
+	}
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"tender-service/utils"
+)
+
+// Middleware authenticates requests by their bearer token. With allowUsername
+// it also accepts the legacy username and requesterUsername query parameters
+// from requests that carry no token.
+func Middleware(tokens *TokenManager, allowUsername bool, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+
+			if header := r.Header.Get("Authorization"); header != "" {
+				token, ok := strings.CutPrefix(header, "Bearer ")
+				if !ok {
+					utils.WriteErrorResponse(w, http.StatusUnauthorized, "Invalid authorization header")
+					return
+				}
+				claims, err := tokens.Parse(strings.TrimSpace(token))
+				if err != nil {
+					logger.InfoContext(ctx, "Rejected token", "error", err)
+					utils.WriteErrorResponse(w, http.StatusUnauthorized, "Invalid token")
+					return
+				}
+				ctx = WithIdentity(ctx, Identity{UserID: claims.Subject, Username: claims.Username, Authenticated: true})
+			} else if allowUsername {
+				ctx = withLegacyAllowed(ctx)
+				username := r.URL.Query().Get("username")
+				if username == "" {
+					username = r.URL.Query().Get("requesterUsername")
+				}
+				ctx = WithLegacyUsername(ctx, username)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
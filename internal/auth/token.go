@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+)
+
+// Claims is the payload of the bearer tokens issued to employees.
+type Claims struct {
+	Subject   string `json:"sub"`
+	Username  string `json:"username"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// TokenManager issues and verifies HS256 JSON Web Tokens signed with a local
+// key.
+type TokenManager struct {
+	key []byte
+	ttl time.Duration
+	now func() time.Time
+}
+
+func NewTokenManager(key []byte, ttl time.Duration) *TokenManager {
+	return &TokenManager{key: key, ttl: ttl, now: time.Now}
+}
+
+// Issue signs a token for the employee that expires after the manager's TTL.
+func (m *TokenManager) Issue(user models.User) (string, time.Time, error) {
+	issuedAt := m.now().UTC()
+	expiresAt := issuedAt.Add(m.ttl)
+
+	payload, err := json.Marshal(Claims{
+		Subject:   user.ID,
+		Username:  user.Username,
+		IssuedAt:  issuedAt.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + m.sign(unsigned), expiresAt, nil
+}
+
+// Parse verifies the token's signature and expiry and returns its claims.
+func (m *TokenManager) Parse(token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return Claims{}, fmt.Errorf("%w: malformed token", my_errors.ErrInvalidToken)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: malformed signature", my_errors.ErrInvalidToken)
+	}
+	expected, _ := base64.RawURLEncoding.DecodeString(m.sign(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, expected) {
+		return Claims{}, fmt.Errorf("%w: bad signature", my_errors.ErrInvalidToken)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: malformed payload", my_errors.ErrInvalidToken)
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return Claims{}, fmt.Errorf("%w: malformed payload", my_errors.ErrInvalidToken)
+	}
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: missing subject", my_errors.ErrInvalidToken)
+	}
+	if m.now().Unix() >= claims.ExpiresAt {
+		return Claims{}, fmt.Errorf("%w: token expired", my_errors.ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+func (m *TokenManager) sign(unsigned string) string {
+	mac := hmac.New(sha256.New, m.key)
+	mac.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenManager_RoundTrip(t *testing.T) {
+	tokens := NewTokenManager([]byte("key"), time.Hour)
+
+	token, expiresAt, err := tokens.Issue(models.User{ID: "user-id", Username: "alice"})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	claims, err := tokens.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-id", claims.Subject)
+	assert.Equal(t, "alice", claims.Username)
+}
+
+func TestTokenManager_RejectsForgedAndExpiredTokens(t *testing.T) {
+	tokens := NewTokenManager([]byte("key"), time.Hour)
+	token, _, err := tokens.Issue(models.User{ID: "user-id", Username: "alice"})
+	require.NoError(t, err)
+
+	other := NewTokenManager([]byte("other key"), time.Hour)
+	_, err = other.Parse(token)
+	assert.ErrorIs(t, err, my_errors.ErrInvalidToken)
+
+	parts := strings.Split(token, ".")
+	forged, _, err := other.Issue(models.User{ID: "admin-id", Username: "admin"})
+	require.NoError(t, err)
+	_, err = tokens.Parse(parts[0] + "." + strings.Split(forged, ".")[1] + "." + parts[2])
+	assert.ErrorIs(t, err, my_errors.ErrInvalidToken)
+
+	tokens.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = tokens.Parse(token)
+	assert.ErrorIs(t, err, my_errors.ErrInvalidToken)
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	require.NoError(t, err)
+
+	assert.True(t, CheckPassword(hash, "secret"))
+	assert.False(t, CheckPassword(hash, "Secret"))
+	assert.False(t, CheckPassword("", "secret"))
+}
@@ -11,16 +11,52 @@ var (
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrInvalidCredentials = errors.New("invalid credentials")
 )
 
 var (
-	ErrTenderNotFound        = errors.New("tender not found")
-	ErrTenderHistoryNotFound = errors.New("tender history not found")
+	ErrVersionConflict = errors.New("version conflict")
 )
 
 var (
-	ErrBidNotFound      = errors.New("bid not found")
-	ErrInvalidBidStatus = errors.New("invalid bid status")
-	ErrInvalidUUID      = errors.New("invalid uuid")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrUsernameTaken = errors.New("username is already taken")
+)
+
+var (
+	ErrOrganizationNotFound    = errors.New("organization not found")
+	ErrInvalidOrganizationType = errors.New("invalid organization type")
+	ErrResponsibleExists       = errors.New("user is already responsible for the organization")
+	ErrResponsibleNotFound     = errors.New("user is not responsible for the organization")
+	ErrLastResponsible         = errors.New("organization must keep at least one responsible")
+)
+
+var (
+	ErrTenderNotFound          = errors.New("tender not found")
+	ErrTenderHistoryNotFound   = errors.New("tender history not found")
+	ErrInvalidTenderTransition = errors.New("tender status transition not allowed")
+	ErrTenderNotEditable       = errors.New("tender cannot be edited in its current status")
+	ErrSubmissionClosed        = errors.New("tender submission deadline has passed")
+	ErrTenderNotPublished      = errors.New("tender is not published")
+)
+
+var (
+	ErrBidNotFound        = errors.New("bid not found")
+	ErrBidHistoryNotFound = errors.New("bid history not found")
+	ErrInvalidBidStatus   = errors.New("invalid bid status")
+	ErrInvalidUUID        = errors.New("invalid uuid")
+)
+
+var (
+	ErrInvalidBidDecision    = errors.New("invalid bid decision")
+	ErrInvalidBidTransition  = errors.New("bid status transition not allowed")
+	ErrBidNotEditable        = errors.New("bid cannot be edited in its current status")
+	ErrBidDecisionNotAllowed = errors.New("bid cannot be decided in its current status")
+	ErrBidDecisionSubmitted  = errors.New("bid decision already submitted")
+	ErrTenderAlreadyDecided  = errors.New("tender is closed or already has an approved bid")
+)
+
+var (
+	ErrWebhookNotFound = errors.New("webhook not found")
 )
@@ -0,0 +1,66 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// Subscriber reacts to events. An event may be handled more than once: when
+// any subscriber fails, the whole event is delivered again later.
+type Subscriber interface {
+	Handle(ctx context.Context, event Event) error
+}
+
+// SubscriberFunc adapts a function to Subscriber.
+type SubscriberFunc func(ctx context.Context, event Event) error
+
+func (f SubscriberFunc) Handle(ctx context.Context, event Event) error {
+	return f(ctx, event)
+}
+
+// Bus delivers each event to every subscriber in the order they subscribed.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []Subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+func (b *Bus) Subscribe(subscriber Subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, subscriber)
+}
+
+// Dispatch hands the event to all subscribers, even after one of them fails,
+// and returns their joined errors.
+func (b *Bus) Dispatch(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, subscriber := range subscribers {
+		if err := subscriber.Handle(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LogSubscriber writes every event to logger.
+func LogSubscriber(logger *slog.Logger) Subscriber {
+	return SubscriberFunc(func(ctx context.Context, event Event) error {
+		actor := event.Metadata().ActorID
+		if actor == "" {
+			actor = "system"
+		}
+		logger.InfoContext(ctx, "Event", "type", event.Type(), "subject_id", event.SubjectID(), "actor_id", actor)
+		return nil
+	})
+}
@@ -0,0 +1,186 @@
+// Package event describes the lifecycle changes of tenders and bids and
+// delivers them to subscribers, so that notifications, audit and caches can
+// react to them without changes to the services that make them.
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"tender-service/internal/models"
+)
+
+// Type names an event. The names are stable and safe to store or send to
+// external systems.
+type Type string
+
+const (
+	TypeTenderCreated        Type = "tender.created"
+	TypeTenderPublished      Type = "tender.published"
+	TypeTenderClosed         Type = "tender.closed"
+	TypeTenderEdited         Type = "tender.edited"
+	TypeTenderRolledBack     Type = "tender.rolled_back"
+	TypeBidCreated           Type = "bid.created"
+	TypeBidStatusChanged     Type = "bid.status_changed"
+	TypeBidDecisionSubmitted Type = "bid.decision_submitted"
+	TypeFeedbackAdded        Type = "bid.feedback_added"
+)
+
+// Types lists every event type.
+var Types = []Type{
+	TypeTenderCreated,
+	TypeTenderPublished,
+	TypeTenderClosed,
+	TypeTenderEdited,
+	TypeTenderRolledBack,
+	TypeBidCreated,
+	TypeBidStatusChanged,
+	TypeBidDecisionSubmitted,
+	TypeFeedbackAdded,
+}
+
+// Known reports whether t is one of Types.
+func (t Type) Known() bool {
+	for _, known := range Types {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a change that has already been stored.
+type Event interface {
+	Type() Type
+	Metadata() Meta
+	// SubjectID is the ID of the tender or bid the event is about.
+	SubjectID() string
+}
+
+// Meta is common to all events.
+type Meta struct {
+	OccurredAt time.Time `json:"occurredAt"`
+	// ActorID is the employee who made the change. It is empty for changes
+	// the service makes on its own, such as closing a tender after its
+	// deadline.
+	ActorID string `json:"actorId,omitempty"`
+}
+
+// NewMeta stamps an event made now by actorID.
+func NewMeta(actorID string) Meta {
+	return Meta{OccurredAt: time.Now().UTC(), ActorID: actorID}
+}
+
+func (m Meta) Metadata() Meta {
+	return m
+}
+
+type TenderCreated struct {
+	Meta
+	Tender models.Tender `json:"tender"`
+}
+
+type TenderPublished struct {
+	Meta
+	Tender models.Tender `json:"tender"`
+}
+
+type TenderClosed struct {
+	Meta
+	Tender models.Tender `json:"tender"`
+}
+
+type TenderEdited struct {
+	Meta
+	Tender models.Tender `json:"tender"`
+}
+
+// TenderRolledBack is a tender whose contents were restored from Version.
+type TenderRolledBack struct {
+	Meta
+	Tender  models.Tender `json:"tender"`
+	Version int           `json:"version"`
+}
+
+type BidCreated struct {
+	Meta
+	Bid models.Bid `json:"bid"`
+}
+
+// BidStatusChanged is a bid that moved from PreviousStatus to Bid.Status,
+// whether by its author or as the outcome of decisions.
+type BidStatusChanged struct {
+	Meta
+	Bid            models.Bid       `json:"bid"`
+	PreviousStatus models.BidStatus `json:"previousStatus"`
+}
+
+// BidDecisionSubmitted is a single responsible's decision. The bid only
+// changes status once the decisions reach an outcome, which is reported as
+// BidStatusChanged.
+type BidDecisionSubmitted struct {
+	Meta
+	Bid      models.Bid         `json:"bid"`
+	Decision models.BidDecision `json:"decision"`
+}
+
+type FeedbackAdded struct {
+	Meta
+	Bid      models.Bid `json:"bid"`
+	Feedback string     `json:"feedback"`
+}
+
+func (TenderCreated) Type() Type        { return TypeTenderCreated }
+func (TenderPublished) Type() Type      { return TypeTenderPublished }
+func (TenderClosed) Type() Type         { return TypeTenderClosed }
+func (TenderEdited) Type() Type         { return TypeTenderEdited }
+func (TenderRolledBack) Type() Type     { return TypeTenderRolledBack }
+func (BidCreated) Type() Type           { return TypeBidCreated }
+func (BidStatusChanged) Type() Type     { return TypeBidStatusChanged }
+func (BidDecisionSubmitted) Type() Type { return TypeBidDecisionSubmitted }
+func (FeedbackAdded) Type() Type        { return TypeFeedbackAdded }
+
+func (e TenderCreated) SubjectID() string        { return e.Tender.ID }
+func (e TenderPublished) SubjectID() string      { return e.Tender.ID }
+func (e TenderClosed) SubjectID() string         { return e.Tender.ID }
+func (e TenderEdited) SubjectID() string         { return e.Tender.ID }
+func (e TenderRolledBack) SubjectID() string     { return e.Tender.ID }
+func (e BidCreated) SubjectID() string           { return e.Bid.ID }
+func (e BidStatusChanged) SubjectID() string     { return e.Bid.ID }
+func (e BidDecisionSubmitted) SubjectID() string { return e.Bid.ID }
+func (e FeedbackAdded) SubjectID() string        { return e.Bid.ID }
+
+// Decode restores an event of the given type from its JSON encoding.
+func Decode(eventType Type, payload []byte) (Event, error) {
+	switch eventType {
+	case TypeTenderCreated:
+		return decode[TenderCreated](payload)
+	case TypeTenderPublished:
+		return decode[TenderPublished](payload)
+	case TypeTenderClosed:
+		return decode[TenderClosed](payload)
+	case TypeTenderEdited:
+		return decode[TenderEdited](payload)
+	case TypeTenderRolledBack:
+		return decode[TenderRolledBack](payload)
+	case TypeBidCreated:
+		return decode[BidCreated](payload)
+	case TypeBidStatusChanged:
+		return decode[BidStatusChanged](payload)
+	case TypeBidDecisionSubmitted:
+		return decode[BidDecisionSubmitted](payload)
+	case TypeFeedbackAdded:
+		return decode[FeedbackAdded](payload)
+	default:
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+}
+
+func decode[E Event](payload []byte) (Event, error) {
+	var event E
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("decoding %s event: %w", event.Type(), err)
+	}
+	return event, nil
+}
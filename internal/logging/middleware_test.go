@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_PropagatesRequestID(t *testing.T) {
+	var out bytes.Buffer
+	logger := New(&out, FormatJSON, slog.LevelInfo)
+	handler := Middleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "Handling request")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+	req.Header.Set(RequestIDHeader, "abc-123")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, "abc-123", rr.Header().Get(RequestIDHeader))
+	decoder := json.NewDecoder(&out)
+	var records []map[string]interface{}
+	for decoder.More() {
+		var record map[string]interface{}
+		require.NoError(t, decoder.Decode(&record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, "Handling request", records[0]["msg"])
+	assert.Equal(t, "abc-123", records[0]["request_id"])
+	assert.Equal(t, "Request served", records[1]["msg"])
+	assert.Equal(t, "abc-123", records[1]["request_id"])
+	assert.Equal(t, float64(http.StatusTeapot), records[1]["status"])
+}
+
+func TestMiddleware_GeneratesRequestID(t *testing.T) {
+	var seen string
+	handler := Middleware(Discard())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	for _, incoming := range []string{"", "has space", string(bytes.Repeat([]byte("a"), maxRequestIDLength+1))} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, incoming)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.NotEmpty(t, seen)
+		assert.NotEqual(t, incoming, seen)
+		assert.Equal(t, seen, rr.Header().Get(RequestIDHeader))
+	}
+}
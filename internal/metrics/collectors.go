@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Counter is a value that only goes up, with one series per combination of
+// label values.
+type Counter struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{name: name, help: help, labelNames: labelNames, series: make(map[string]*counterSeries)}
+}
+
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the series for labelValues by delta, which must not be
+// negative.
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.name))
+	}
+	key := seriesKey(c.labelNames, labelValues, c.name)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	series, ok := c.series[key]
+	if !ok {
+		series = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = series
+	}
+	series.value += delta
+}
+
+func (c *Counter) collect() family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := family{name: c.name, help: c.help, kind: "counter"}
+	// A counter without labels has a single series that exists from the
+	// start, so that rates work from the first scrape.
+	if len(c.labelNames) == 0 && len(c.series) == 0 {
+		f.samples = append(f.samples, sample{})
+	}
+	for _, key := range sortedKeys(c.series) {
+		series := c.series[key]
+		f.samples = append(f.samples, sample{labels: pairs(c.labelNames, series.labelValues), value: series.value})
+	}
+	return f
+}
+
+// DefaultBuckets suit durations in seconds from a millisecond to ten seconds.
+var DefaultBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Histogram counts observations in buckets, with one series per combination
+// of label values.
+type Histogram struct {
+	name       string
+	help       string
+	buckets    []float64
+	labelNames []string
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	// counts holds the observations that fell into each bucket alone; they
+	// are accumulated on collection.
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram returns a histogram with the given upper bucket bounds, or
+// DefaultBuckets when buckets is empty.
+func NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{name: name, help: help, buckets: buckets, labelNames: labelNames, series: make(map[string]*histogramSeries)}
+}
+
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	key := seriesKey(h.labelNames, labelValues, h.name)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{labelValues: append([]string(nil), labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		series.counts[i]++
+	}
+	series.count++
+	series.sum += value
+}
+
+func (h *Histogram) collect() family {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f := family{name: h.name, help: h.help, kind: "histogram"}
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		labels := pairs(h.labelNames, series.labelValues)
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			f.samples = append(f.samples, sample{
+				suffix: "_bucket",
+				labels: append(labels[:len(labels):len(labels)], labelPair{name: "le", value: formatValue(bound)}),
+				value:  float64(cumulative),
+			})
+		}
+		f.samples = append(f.samples,
+			sample{suffix: "_bucket", labels: append(labels[:len(labels):len(labels)], labelPair{name: "le", value: "+Inf"}), value: float64(series.count)},
+			sample{suffix: "_sum", labels: labels, value: series.sum},
+			sample{suffix: "_count", labels: labels, value: float64(series.count)},
+		)
+	}
+	return f
+}
+
+// funcMetric reads its only value from a function on every collection.
+type funcMetric struct {
+	name  string
+	help  string
+	kind  string
+	value func() float64
+}
+
+// NewGaugeFunc returns a gauge whose value is read from value on every scrape.
+func NewGaugeFunc(name, help string, value func() float64) Collector {
+	return &funcMetric{name: name, help: help, kind: "gauge", value: value}
+}
+
+// NewCounterFunc returns a counter whose value is read from value on every
+// scrape, for totals that are kept elsewhere.
+func NewCounterFunc(name, help string, value func() float64) Collector {
+	return &funcMetric{name: name, help: help, kind: "counter", value: value}
+}
+
+func (m *funcMetric) collect() family {
+	return family{name: m.name, help: m.help, kind: m.kind, samples: []sample{{value: m.value()}}}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"database/sql"
+)
+
+// RegisterDBStats exposes the connection pool statistics of db.
+func RegisterDBStats(registry *Registry, db *sql.DB) {
+	stat := func(value func(sql.DBStats) float64) func() float64 {
+		return func() float64 { return value(db.Stats()) }
+	}
+	registry.MustRegister(
+		NewGaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.",
+			stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) })),
+		NewGaugeFunc("db_open_connections", "Number of established connections, both in use and idle.",
+			stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) })),
+		NewGaugeFunc("db_in_use_connections", "Number of connections currently in use.",
+			stat(func(s sql.DBStats) float64 { return float64(s.InUse) })),
+		NewGaugeFunc("db_idle_connections", "Number of idle connections.",
+			stat(func(s sql.DBStats) float64 { return float64(s.Idle) })),
+		NewCounterFunc("db_wait_count_total", "Total number of connections waited for.",
+			stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) })),
+		NewCounterFunc("db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+			stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() })),
+		NewCounterFunc("db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.",
+			stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) })),
+		NewCounterFunc("db_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.",
+			stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) })),
+		NewCounterFunc("db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.",
+			stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) })),
+	)
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"context"
+
+	"tender-service/internal/event"
+)
+
+// EventCounter counts business events: tenders created and published, bids
+// created and decisions by outcome. Pass it to repository.OnCommit so that
+// each event is counted once, when the change it describes commits, rather
+// than as the outbox delivers it, which may happen more than once.
+func EventCounter(registry *Registry) func(ctx context.Context, events []event.Event) {
+	tendersCreated := NewCounter("tenders_created_total", "Total number of tenders created.")
+	tendersPublished := NewCounter("tenders_published_total", "Total number of tenders published.")
+	bidsCreated := NewCounter("bids_created_total", "Total number of bids created.")
+	bidDecisions := NewCounter("bid_decisions_total", "Total number of decisions submitted on bids by outcome.", "decision")
+	registry.MustRegister(tendersCreated, tendersPublished, bidsCreated, bidDecisions)
+
+	return func(ctx context.Context, events []event.Event) {
+		for _, e := range events {
+			switch e := e.(type) {
+			case event.TenderCreated:
+				tendersCreated.Inc()
+			case event.TenderPublished:
+				tendersPublished.Inc()
+			case event.BidCreated:
+				bidsCreated.Inc()
+			case event.BidDecisionSubmitted:
+				bidDecisions.Inc(string(e.Decision))
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// HTTPHandler wraps router and records the duration of every request by
+// method, route and status. The route is the mux path template, such as
+// /api/tenders/{tenderId}/status, so that IDs do not multiply the series.
+// Requests that match no route, answered with 404 or 405, are recorded under
+// the route "unmatched".
+func HTTPHandler(registry *Registry, router *mux.Router) http.Handler {
+	duration := NewHistogram("http_request_duration_seconds", "Duration of HTTP requests by route.", nil, "method", "route", "status")
+	registry.MustRegister(duration)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := "unmatched"
+		var match mux.RouteMatch
+		if router.Match(r, &match) && match.MatchErr == nil && match.Route != nil {
+			if template, err := match.Route.GetPathTemplate(); err == nil {
+				route = template
+			}
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		router.ServeHTTP(recorder, r)
+		duration.Observe(time.Since(start).Seconds(), r.Method, route, strconv.Itoa(recorder.status))
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(body []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(body)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
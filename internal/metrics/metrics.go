@@ -0,0 +1,168 @@
+// Package metrics keeps counters, histograms and gauges and exposes them in
+// the Prometheus text exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Collector is a metric family that a Registry can expose.
+type Collector interface {
+	// collect snapshots the family for exposition.
+	collect() family
+}
+
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples []sample
+}
+
+type sample struct {
+	// suffix is appended to the family name, such as _bucket for histograms.
+	suffix string
+	labels []labelPair
+	value  float64
+}
+
+type labelPair struct {
+	name  string
+	value string
+}
+
+// Registry exposes the collectors registered with it.
+type Registry struct {
+	mu         sync.Mutex
+	collectors map[string]Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]Collector)}
+}
+
+// MustRegister adds collectors to the registry. It panics when a name is
+// already taken, which is a programming error.
+func (r *Registry) MustRegister(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, collector := range collectors {
+		name := collector.collect().name
+		if _, ok := r.collectors[name]; ok {
+			panic(fmt.Sprintf("metrics: %s is already registered", name))
+		}
+		r.collectors[name] = collector
+	}
+}
+
+// WriteText writes every registered family, sorted by name, in the text
+// exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	collectors := make([]Collector, 0, len(r.collectors))
+	for _, collector := range r.collectors {
+		collectors = append(collectors, collector)
+	}
+	r.mu.Unlock()
+
+	families := make([]family, len(collectors))
+	for i, collector := range collectors {
+		families[i] = collector.collect()
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	buf := bufio.NewWriter(w)
+	for _, f := range families {
+		fmt.Fprintf(buf, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(buf, "# TYPE %s %s\n", f.name, f.kind)
+		for _, s := range f.samples {
+			buf.WriteString(f.name)
+			buf.WriteString(s.suffix)
+			writeLabels(buf, s.labels)
+			buf.WriteByte(' ')
+			buf.WriteString(formatValue(s.value))
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Flush()
+}
+
+// ServeHTTP serves the registry to Prometheus scrapers.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+func writeLabels(buf *bufio.Writer, labels []labelPair) {
+	if len(labels) == 0 {
+		return
+	}
+	buf.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(label.name)
+		buf.WriteString(`="`)
+		buf.WriteString(labelValueEscaper.Replace(label.value))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte('}')
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// seriesKey identifies the series of labelValues by joining them with a byte
+// that cannot appear in valid UTF-8.
+func seriesKey(labelNames, labelValues []string, name string) string {
+	if len(labelValues) != len(labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects labels %v, got %d values", name, labelNames, len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+func pairs(labelNames, labelValues []string) []labelPair {
+	labels := make([]labelPair, len(labelNames))
+	for i, name := range labelNames {
+		labels[i] = labelPair{name: name, value: labelValues[i]}
+	}
+	return labels
+}
+
+func sortedKeys[T any](series map[string]T) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
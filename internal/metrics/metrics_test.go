@@ -0,0 +1,102 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, registry *Registry) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	registry.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rr.Header().Get("Content-Type"))
+	return rr.Body.String()
+}
+
+func TestRegistry_WritesTextFormat(t *testing.T) {
+	registry := NewRegistry()
+	requests := NewCounter("requests_total", "Total requests.", "path")
+	latency := NewHistogram("latency_seconds", "Latency.\nIn seconds.", []float64{0.1, 1})
+	registry.MustRegister(requests, latency, NewGaugeFunc("up", "Whether it is up.", func() float64 { return 1 }))
+
+	requests.Inc(`/a"b`)
+	requests.Add(2, `/a"b`)
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(3)
+
+	assert.Equal(t, `# HELP latency_seconds Latency.\nIn seconds.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 3.55
+latency_seconds_count 3
+# HELP requests_total Total requests.
+# TYPE requests_total counter
+requests_total{path="/a\"b"} 3
+# HELP up Whether it is up.
+# TYPE up gauge
+up 1
+`, scrape(t, registry))
+
+	assert.Panics(t, func() { registry.MustRegister(NewCounter("up", "Duplicate.")) })
+}
+
+func TestHTTPHandler_LabelsByRouteTemplate(t *testing.T) {
+	registry := NewRegistry()
+	router := mux.NewRouter()
+	router.HandleFunc("/api/tenders/{tenderId}/status", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+	handler := HTTPHandler(registry, router)
+
+	for _, id := range []string{"1", "2"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/tenders/"+id+"/status", nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/nowhere", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/tenders/1/status", nil))
+
+	body := scrape(t, registry)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/api/tenders/{tenderId}/status",status="404"} 2`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="DELETE",route="unmatched",status="405"} 1`)
+}
+
+func TestEventCounter_CountsBusinessEvents(t *testing.T) {
+	registry := NewRegistry()
+	count := EventCounter(registry)
+
+	count(context.Background(), []event.Event{
+		event.TenderCreated{},
+		event.TenderPublished{},
+		event.BidCreated{},
+		event.BidCreated{},
+		event.BidDecisionSubmitted{Decision: models.BidDecisionApproved},
+		event.BidDecisionSubmitted{Decision: models.BidDecisionRejected},
+		event.BidDecisionSubmitted{Decision: models.BidDecisionRejected},
+		event.FeedbackAdded{},
+	})
+
+	body := scrape(t, registry)
+	for _, line := range []string{
+		"tenders_created_total 1",
+		"tenders_published_total 1",
+		"bids_created_total 2",
+		`bid_decisions_total{decision="Approved"} 1`,
+		`bid_decisions_total{decision="Rejected"} 2`,
+	} {
+		assert.True(t, strings.Contains(body, line+"\n"), "missing %q in\n%s", line, body)
+	}
+}
@@ -1,5 +1,11 @@
 package models
 
+import (
+	"errors"
+	my_errors "tender-service/internal/errors"
+	"time"
+)
+
 type BidAuthorType string
 
 const (
@@ -15,14 +21,117 @@ type Bid struct {
 	AuthorType     BidAuthorType
 	Description    string
 	Status         BidStatus
+	Version        int
 	CreatedAt      string
 	UpdatedAt      string
 }
 
+type BidHistory struct {
+	ID             string
+	BidID          string
+	TenderID       string
+	OrganizationID string
+	UserID         string
+	AuthorType     BidAuthorType
+	Description    string
+	Status         BidStatus
+	Version        int
+	UpdatedAt      string
+}
+
+type BidReview struct {
+	ID          string    `json:"id"`
+	BidID       string    `json:"-"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
 type BidStatus string
 
 const (
 	BidStatusCreated   BidStatus = "CREATED"
 	BidStatusPublished BidStatus = "PUBLISHED"
 	BidStatusCanceled  BidStatus = "CANCELED"
+	BidStatusApproved  BidStatus = "APPROVED"
+	BidStatusRejected  BidStatus = "REJECTED"
+)
+
+// bidTransitions lists the statuses a bid may move to from each status.
+// APPROVED and REJECTED are decision outcomes and are terminal, as is CANCELED.
+var bidTransitions = map[BidStatus][]BidStatus{
+	BidStatusCreated:   {BidStatusPublished, BidStatusCanceled},
+	BidStatusPublished: {BidStatusCanceled, BidStatusApproved, BidStatusRejected},
+}
+
+func ParseBidStatus(status string) (BidStatus, error) {
+	switch status {
+	case string(BidStatusCreated):
+		return BidStatusCreated, nil
+	case string(BidStatusPublished):
+		return BidStatusPublished, nil
+	case string(BidStatusCanceled):
+		return BidStatusCanceled, nil
+	case string(BidStatusApproved):
+		return BidStatusApproved, nil
+	case string(BidStatusRejected):
+		return BidStatusRejected, nil
+	default:
+		return "", errors.New("invalid bid status")
+	}
+}
+
+func (s BidStatus) CanTransitionTo(next BidStatus) bool {
+	for _, allowed := range bidTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDecisionOutcome reports whether the status can only be reached through
+// the decision workflow rather than set by the bid author.
+func (s BidStatus) IsDecisionOutcome() bool {
+	return s == BidStatusApproved || s == BidStatusRejected
+}
+
+// IsEditable reports whether the author may still change the bid contents.
+func (s BidStatus) IsEditable() bool {
+	return s == BidStatusCreated || s == BidStatusPublished
+}
+
+// TenderVisibleBidStatuses are the statuses in which a bid is visible to the
+// responsibles of the tender's organization. Created and canceled bids are
+// seen only by their authors.
+var TenderVisibleBidStatuses = []BidStatus{BidStatusPublished, BidStatusApproved, BidStatusRejected}
+
+func (s BidStatus) IsVisibleToTender() bool {
+	for _, status := range TenderVisibleBidStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+type BidDecision string
+
+const (
+	BidDecisionApproved BidDecision = "Approved"
+	BidDecisionRejected BidDecision = "Rejected"
 )
+
+// BidDecisionQuorum is the maximum number of approvals a bid needs; tenders of
+// smaller organizations need approval from every responsible.
+const BidDecisionQuorum = 3
+
+func ParseBidDecision(decision string) (BidDecision, error) {
+	switch decision {
+	case string(BidDecisionApproved):
+		return BidDecisionApproved, nil
+	case string(BidDecisionRejected):
+		return BidDecisionRejected, nil
+	default:
+		return "", my_errors.ErrInvalidBidDecision
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type OrganizationType string
+
+const (
+	OrganizationIE  OrganizationType = "IE"
+	OrganizationLLC OrganizationType = "LLC"
+	OrganizationJSC OrganizationType = "JSC"
+)
+
+type Organization struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Type        OrganizationType `json:"type"`
+	CreatedAt   time.Time        `json:"createdAt"`
+	UpdatedAt   time.Time        `json:"updatedAt"`
+}
+
+func ParseOrganizationType(organizationType string) (OrganizationType, error) {
+	switch organizationType {
+	case string(OrganizationIE):
+		return OrganizationIE, nil
+	case string(OrganizationLLC):
+		return OrganizationLLC, nil
+	case string(OrganizationJSC):
+		return OrganizationJSC, nil
+	default:
+		return "", errors.New("invalid organization type")
+	}
+}
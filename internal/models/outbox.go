@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "PENDING"
+	OutboxSent    OutboxStatus = "SENT"
+	OutboxFailed  OutboxStatus = "FAILED"
+)
+
+func ParseOutboxStatus(status string) (OutboxStatus, error) {
+	switch status {
+	case string(OutboxPending):
+		return OutboxPending, nil
+	case string(OutboxSent):
+		return OutboxSent, nil
+	case string(OutboxFailed):
+		return OutboxFailed, nil
+	default:
+		return "", errors.New("invalid outbox status")
+	}
+}
+
+// OutboxEntry is a stored event waiting for, or done with, delivery. A
+// pending entry is retried until it is sent or runs out of attempts, after
+// which it is failed.
+type OutboxEntry struct {
+	ID            int64           `json:"id"`
+	EventType     string          `json:"eventType"`
+	SubjectID     string          `json:"subjectId"`
+	Payload       json.RawMessage `json:"payload"`
+	Status        OutboxStatus    `json:"status"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"lastError,omitempty"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	SentAt        *time.Time      `json:"sentAt,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// TenderSearchResult is a tender matching a search query. Highlight is the
+// matching text with the found words wrapped in <b></b>.
+type TenderSearchResult struct {
+	Tender
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
+}
+
+// BidSearchResult is TenderSearchResult for bids.
+type BidSearchResult struct {
+	Bid
+	Rank      float64 `json:"rank"`
+	Highlight string  `json:"highlight"`
+}
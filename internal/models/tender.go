@@ -24,6 +24,30 @@ type Tender struct {
 	Version        int          `json:"version"`
 	CreatedAt      time.Time    `json:"createdAt"`
 	UpdatedAt      time.Time    `json:"updatedAt"`
+
+	SubmissionDeadline *time.Time `json:"submissionDeadline,omitempty"`
+	DecisionDeadline   *time.Time `json:"decisionDeadline,omitempty"`
+}
+
+// AcceptsBidsAt reports whether bids may still be submitted at the given time.
+func (t Tender) AcceptsBidsAt(at time.Time) bool {
+	return t.SubmissionDeadline == nil || at.Before(*t.SubmissionDeadline)
+}
+
+// ExpiresAt returns when a published tender is closed automatically: at the
+// decision deadline, or at the submission deadline when there is no decision
+// deadline. Tenders without deadlines never expire.
+func (t Tender) ExpiresAt() *time.Time {
+	if t.DecisionDeadline != nil {
+		return t.DecisionDeadline
+	}
+	return t.SubmissionDeadline
+}
+
+// HasValidDeadlines reports whether the decision deadline, if any, does not
+// precede the submission deadline.
+func (t Tender) HasValidDeadlines() bool {
+	return t.SubmissionDeadline == nil || t.DecisionDeadline == nil || !t.DecisionDeadline.Before(*t.SubmissionDeadline)
 }
 
 type ErrorResponse struct {
@@ -41,6 +65,9 @@ type TenderHistory struct {
 	CreatorID      string       `json:"creator_id"`
 	Version        int          `json:"version"`
 	UpdatedAt      time.Time    `json:"updated_at"`
+
+	SubmissionDeadline *time.Time `json:"submission_deadline,omitempty"`
+	DecisionDeadline   *time.Time `json:"decision_deadline,omitempty"`
 }
 
 func ParseTenderStatus(status string) (TenderStatus, error) {
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	my_errors "tender-service/internal/errors"
+)
+
+// TenderGuard inspects a tender before it changes status. It refuses the
+// transition by returning a *TransitionRejection; any other error means the
+// check itself failed. bids reads within the same transaction as the status
+// update.
+type TenderGuard func(ctx context.Context, bids BidCounter, tender Tender) error
+
+// BidCounter is the part of the bid repository that guards may use.
+type BidCounter interface {
+	CountTenderBids(ctx context.Context, tenderID string, statuses []BidStatus) (int, error)
+}
+
+// TransitionRejection is returned by a guard that refuses a transition.
+type TransitionRejection struct {
+	Reason string
+}
+
+func (r *TransitionRejection) Error() string {
+	return r.Reason
+}
+
+// RejectTransition returns a *TransitionRejection with a formatted reason.
+func RejectTransition(format string, args ...interface{}) error {
+	return &TransitionRejection{Reason: fmt.Sprintf(format, args...)}
+}
+
+// TenderStateMachine holds the allowed tender status transitions together with
+// the guards that must pass for each of them.
+type TenderStateMachine struct {
+	transitions map[TenderStatus]map[TenderStatus][]TenderGuard
+}
+
+// NewTenderStateMachine returns the default tender lifecycle:
+// CREATED → PUBLISHED → CLOSED, where a tender may also be closed before it is
+// ever published. CLOSED is terminal.
+func NewTenderStateMachine() *TenderStateMachine {
+	return &TenderStateMachine{
+		transitions: map[TenderStatus]map[TenderStatus][]TenderGuard{
+			Created:   {Published: nil, Closed: nil},
+			Published: {Closed: nil},
+		},
+	}
+}
+
+// AddGuard registers a guard for an existing transition.
+func (m *TenderStateMachine) AddGuard(from, to TenderStatus, guard TenderGuard) {
+	targets, ok := m.transitions[from]
+	if !ok {
+		panic(fmt.Sprintf("tender transition %s -> %s is not defined", from, to))
+	}
+	guards, ok := targets[to]
+	if !ok {
+		panic(fmt.Sprintf("tender transition %s -> %s is not defined", from, to))
+	}
+	targets[to] = append(guards, guard)
+}
+
+func (m *TenderStateMachine) CanTransition(from, to TenderStatus) bool {
+	_, ok := m.transitions[from][to]
+	return ok
+}
+
+// Transition checks that the tender may move to the given status. Every
+// rejection wraps ErrInvalidTenderTransition; errors from guards that failed
+// to check are returned unchanged.
+func (m *TenderStateMachine) Transition(ctx context.Context, bids BidCounter, tender Tender, to TenderStatus) error {
+	guards, ok := m.transitions[tender.Status][to]
+	if !ok {
+		return fmt.Errorf("%w: %s -> %s", my_errors.ErrInvalidTenderTransition, tender.Status, to)
+	}
+	for _, guard := range guards {
+		if err := guard(ctx, bids, tender); err != nil {
+			var rejection *TransitionRejection
+			if errors.As(err, &rejection) {
+				return fmt.Errorf("%w: %s", my_errors.ErrInvalidTenderTransition, rejection.Reason)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEditable reports whether the tender contents may still be changed.
+func (s TenderStatus) IsEditable() bool {
+	return s != Closed
+}
+
+// RequireTenderDescription blocks publishing a tender without a description.
+func RequireTenderDescription(ctx context.Context, bids BidCounter, tender Tender) error {
+	if tender.Description == "" {
+		return RejectTransition("cannot publish a tender with an empty description")
+	}
+	return nil
+}
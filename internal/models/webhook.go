@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookSubscription asks for the events concerning an organization to be
+// POSTed to URL, signed with Secret. Only the event types in EventTypes are
+// sent, or all of them when it is empty.
+//
+// A subscription whose deliveries keep failing is disabled: Active turns
+// false and DisabledAt records when. Turning it back on resumes the pending
+// deliveries.
+type WebhookSubscription struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organizationId"`
+	URL            string `json:"url"`
+	// Secret is only returned when the subscription is created.
+	Secret              string     `json:"secret,omitempty"`
+	EventTypes          []string   `json:"eventTypes"`
+	Active              bool       `json:"active"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	DisabledAt          *time.Time `json:"disabledAt,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+}
+
+// Wants reports whether the subscription asks for events of eventType.
+func (s WebhookSubscription) Wants(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, wanted := range s.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookDelivery is one event sent, or to be sent, to one subscription.
+// EventID is the ID of the event's outbox entry, so each event is delivered
+// to a subscription once however often it is fanned out.
+type WebhookDelivery struct {
+	ID             string                `json:"id"`
+	SubscriptionID string                `json:"subscriptionId"`
+	EventID        int64                 `json:"eventId"`
+	EventType      string                `json:"eventType"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	// ResponseStatus is the HTTP status of the last attempt, zero when the
+	// request got no response.
+	ResponseStatus int        `json:"responseStatus,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty"`
+}
@@ -0,0 +1,160 @@
+// Package outbox delivers the events stored in the outbox to sinks.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+)
+
+const (
+	// batchSize bounds how many entries one poll claims.
+	batchSize = 100
+	// lease is how long a claimed entry stays hidden from other relays. It
+	// must comfortably exceed the time sinks take to handle a batch.
+	lease = time.Minute
+	// maxAttempts is how many deliveries an entry gets before it is failed.
+	maxAttempts = 10
+
+	minBackoff = time.Second
+	maxBackoff = 10 * time.Minute
+)
+
+// Sink receives outbox entries. An error makes the relay deliver the entry
+// again later, so sinks must tolerate duplicates.
+type Sink interface {
+	Deliver(ctx context.Context, entry models.OutboxEntry) error
+}
+
+// SinkFunc adapts a function to Sink.
+type SinkFunc func(ctx context.Context, entry models.OutboxEntry) error
+
+func (f SinkFunc) Deliver(ctx context.Context, entry models.OutboxEntry) error {
+	return f(ctx, entry)
+}
+
+// EventSink decodes entries and dispatches them on bus.
+func EventSink(bus *event.Bus) Sink {
+	return SinkFunc(func(ctx context.Context, entry models.OutboxEntry) error {
+		e, err := event.Decode(event.Type(entry.EventType), entry.Payload)
+		if err != nil {
+			return err
+		}
+		return bus.Dispatch(ctx, e)
+	})
+}
+
+// Relay polls the outbox and delivers due entries to every registered sink
+// with at-least-once semantics. Several relays, one per replica, may share an
+// outbox: each entry is claimed by one of them at a time.
+type Relay struct {
+	repo     repository.OutboxRepository
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+func NewRelay(repo repository.OutboxRepository, interval time.Duration, logger *slog.Logger) *Relay {
+	return &Relay{repo: repo, interval: interval, logger: logger}
+}
+
+func (r *Relay) Register(sink Sink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sinks = append(r.sinks, sink)
+}
+
+// Run delivers due entries every interval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.DeliverDue(ctx, time.Now()); err != nil {
+			r.logger.ErrorContext(ctx, "Error delivering outbox entries", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue delivers the entries due at now, batch by batch, and returns how
+// many were sent.
+func (r *Relay) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	for {
+		entries, err := r.repo.ClaimDue(ctx, now, lease, batchSize)
+		if err != nil {
+			return sent, err
+		}
+		for _, entry := range entries {
+			ok, err := r.deliver(ctx, entry, now)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+		if len(entries) < batchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver hands the entry to all sinks and records the outcome. It reports
+// whether the entry was sent; the error is about recording the outcome.
+func (r *Relay) deliver(ctx context.Context, entry models.OutboxEntry, now time.Time) (bool, error) {
+	r.mu.RLock()
+	sinks := r.sinks
+	r.mu.RUnlock()
+
+	var deliveryErr error
+	for _, sink := range sinks {
+		if err := sink.Deliver(ctx, entry); err != nil {
+			deliveryErr = err
+			break
+		}
+	}
+
+	if deliveryErr == nil {
+		return true, r.repo.MarkSent(ctx, entry.ID, now)
+	}
+
+	final := entry.Attempts >= maxAttempts
+	if final {
+		r.logger.ErrorContext(ctx, "Giving up on outbox entry", "entry_id", entry.ID, "event_type", entry.EventType, "subject_id", entry.SubjectID, "attempts", entry.Attempts, "error", deliveryErr)
+	} else {
+		r.logger.WarnContext(ctx, "Error delivering outbox entry", "entry_id", entry.ID, "event_type", entry.EventType, "subject_id", entry.SubjectID, "attempt", entry.Attempts, "error", deliveryErr)
+	}
+	if err := r.repo.MarkFailed(ctx, entry.ID, deliveryErr.Error(), now.Add(Backoff(entry.Attempts)), final); err != nil {
+		return false, fmt.Errorf("recording failure of outbox entry %d: %w", entry.ID, err)
+	}
+	return false, nil
+}
+
+// Backoff is the delay after the given failed attempt: it doubles from
+// minBackoff with every attempt up to maxBackoff.
+func Backoff(attempt int) time.Duration {
+	delay := minBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"tender-service/internal/event"
+	"tender-service/internal/logging"
+	"tender-service/internal/models"
+	"tender-service/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, Backoff(1))
+	assert.Equal(t, 2*time.Second, Backoff(2))
+	assert.Equal(t, 8*time.Second, Backoff(4))
+	assert.Equal(t, maxBackoff, Backoff(30))
+}
+
+func TestRelay_FailsEntryAfterMaxAttempts(t *testing.T) {
+	repo := memory.NewOutboxRepository(memory.NewStore())
+	require.NoError(t, repo.Append(context.Background(), event.TenderCreated{Tender: models.Tender{ID: "tender"}}))
+
+	attempts := 0
+	relay := NewRelay(repo, time.Second, logging.Discard())
+	relay.Register(SinkFunc(func(ctx context.Context, entry models.OutboxEntry) error {
+		attempts++
+		return errors.New("sink unavailable")
+	}))
+
+	now := time.Now()
+	for i := 0; i < maxAttempts+2; i++ {
+		sent, err := relay.DeliverDue(context.Background(), now)
+		require.NoError(t, err)
+		assert.Zero(t, sent)
+		now = now.Add(maxBackoff)
+	}
+	assert.Equal(t, maxAttempts, attempts)
+
+	entries, err := repo.ListOutbox(context.Background(), []models.OutboxStatus{models.OutboxFailed}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, maxAttempts, entries[0].Attempts)
+	assert.Equal(t, "sink unavailable", entries[0].LastError)
+}
@@ -1,55 +1,94 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"tender-service/internal/models"
+	"time"
 
 	my_errors "tender-service/internal/errors"
+
+	"github.com/lib/pq"
 )
 
 type BidRepository interface {
-	CreateBid(bid *models.Bid) (*models.Bid, error)
-	GetBidsByTenderID(tenderID string, limit, offset int) ([]models.Bid, error)
-	GetBidsByUserID(userID string, limit, offset int) ([]models.Bid, error)
-	GetBidByID(bidID string) (*models.Bid, error)
-	UpdateBidStatus(bidID string, status models.BidStatus) error
-	EditBid(bidID string, updates map[string]interface{}) error
-	AddBidFeedback(bidID, feedback string) error
+	CreateBid(ctx context.Context, bid *models.Bid) (*models.Bid, error)
+	GetBidsByTenderID(ctx context.Context, tenderID string, statuses []models.BidStatus, options QueryOptions) ([]models.Bid, error)
+	GetBidsByUserID(ctx context.Context, userID string, options QueryOptions) ([]models.Bid, error)
+	SearchBids(ctx context.Context, query string, visibility BidVisibility, options QueryOptions) ([]models.BidSearchResult, error)
+	GetBidByID(ctx context.Context, bidID string) (*models.Bid, error)
+	// GetBidForUpdate is GetBidByID that, within a UnitOfWork, also keeps
+	// other units from changing or locking the bid until it ends.
+	GetBidForUpdate(ctx context.Context, bidID string) (*models.Bid, error)
+	UpdateBidStatus(ctx context.Context, bidID string, status models.BidStatus, version int) (*models.Bid, error)
+	EditBid(ctx context.Context, bidID string, updates map[string]interface{}, version int) (*models.Bid, error)
+	AddBidFeedback(ctx context.Context, bidID, feedback string) error
+	AddBidDecision(ctx context.Context, bidID, userID string, decision models.BidDecision) error
+	CountBidDecisions(ctx context.Context, bidID string) (approved, rejected int, err error)
+	GetBidHistoryByVersion(ctx context.Context, bidID string, version int) (models.BidHistory, error)
+	HasUserBidForTender(ctx context.Context, userID, tenderID string) (bool, error)
+	CountTenderBids(ctx context.Context, tenderID string, statuses []models.BidStatus) (int, error)
+	GetBidReviewsByAuthorID(ctx context.Context, authorID string, options QueryOptions) ([]models.BidReview, error)
+}
+
+// BidVisibility selects the bids a user may see: those they authored, those
+// authored on behalf of one of OrganizationIDs, and those with one of
+// TenderStatuses on tenders of OrganizationIDs.
+type BidVisibility struct {
+	UserID          string
+	OrganizationIDs []string
+	TenderStatuses  []models.BidStatus
 }
 
 type bidRepository struct {
-	db *sql.DB
+	db           querier
+	queryTimeout time.Duration
 }
 
-func NewBidRepository(db *sql.DB) BidRepository {
-	return &bidRepository{db: db}
+func NewBidRepository(db *sql.DB, queryTimeout time.Duration) BidRepository {
+	return &bidRepository{db: instrument(db, "bid"), queryTimeout: queryTimeout}
 }
 
-func (r *bidRepository) CreateBid(bid *models.Bid) (*models.Bid, error) {
+func (r *bidRepository) CreateBid(ctx context.Context, bid *models.Bid) (*models.Bid, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
         INSERT INTO bid (description, tender_id, organization_id, user_id, author_type, status, created_at, updated_at)
         VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
-        RETURNING id, description, tender_id, organization_id, user_id, author_type, status, created_at, updated_at
+        RETURNING id, description, tender_id, organization_id, user_id, author_type, status, version, created_at, updated_at
     `
-	err := r.db.QueryRow(query, bid.Description, bid.TenderID, bid.OrganizationID, bid.UserID, bid.AuthorType, bid.Status).
-		Scan(&bid.ID, &bid.Description, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.AuthorType, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, bid.Description, bid.TenderID, bid.OrganizationID, bid.UserID, bid.AuthorType, bid.Status).
+		Scan(&bid.ID, &bid.Description, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.AuthorType, &bid.Status, &bid.Version, &bid.CreatedAt, &bid.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
 	return bid, nil
 }
 
-func (r *bidRepository) GetBidsByTenderID(tenderID string, limit, offset int) ([]models.Bid, error) {
+// GetBidsByTenderID returns the tender's bids that have one of statuses and
+// match options.
+func (r *bidRepository) GetBidsByTenderID(ctx context.Context, tenderID string, statuses []models.BidStatus, options QueryOptions) ([]models.Bid, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var bids []models.Bid
 
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
 	query := `
-        SELECT id, description, tender_id, organization_id, user_id, status, created_at, updated_at 
-        FROM bid 
-        WHERE tender_id = $1 
-        LIMIT $2 OFFSET $3
-    `
-	rows, err := r.db.Query(query, tenderID, limit, offset)
+        SELECT id, description, tender_id, organization_id, user_id, author_type, status, version, created_at, updated_at 
+        FROM bid
+        WHERE tender_id = $1 AND status::text = ANY($2)`
+	args := []interface{}{tenderID, pq.Array(statusValues)}
+	conditions, args := options.filterSQL(bidListColumns, args)
+	page, args := options.pageSQL(bidListColumns, args)
+
+	rows, err := r.db.QueryContext(ctx, query+conditions+page, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -57,25 +96,29 @@ func (r *bidRepository) GetBidsByTenderID(tenderID string, limit, offset int) ([
 
 	for rows.Next() {
 		var bid models.Bid
-		if err := rows.Scan(&bid.ID, &bid.Description, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt); err != nil {
+		if err := rows.Scan(&bid.ID, &bid.Description, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.AuthorType, &bid.Status, &bid.Version, &bid.CreatedAt, &bid.UpdatedAt); err != nil {
 			return nil, err
 		}
 		bids = append(bids, bid)
 	}
-
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return bids, nil
 }
 
-func (r *bidRepository) GetBidsByUserID(userID string, limit, offset int) ([]models.Bid, error) {
+func (r *bidRepository) GetBidsByUserID(ctx context.Context, userID string, options QueryOptions) ([]models.Bid, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
-        SELECT id, description, tender_id, organization_id, user_id, status, created_at, updated_at
+        SELECT id, description, tender_id, organization_id, user_id, author_type, status, version, created_at, updated_at
         FROM bid
-        WHERE user_id = $1
-        ORDER BY description
-        LIMIT $2 OFFSET $3
-    `
+        WHERE user_id = $1`
+	conditions, args := options.filterSQL(bidListColumns, []interface{}{userID})
+	page, args := options.pageSQL(bidListColumns, args)
 
-	rows, err := r.db.Query(query, userID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query+conditions+page, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -84,47 +127,35 @@ func (r *bidRepository) GetBidsByUserID(userID string, limit, offset int) ([]mod
 	var bids []models.Bid
 	for rows.Next() {
 		var bid models.Bid
-		if err := rows.Scan(&bid.ID, &bid.Description, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt); err != nil {
+		if err := rows.Scan(&bid.ID, &bid.Description, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.AuthorType, &bid.Status, &bid.Version, &bid.CreatedAt, &bid.UpdatedAt); err != nil {
 			return nil, err
 		}
 		bids = append(bids, bid)
 	}
-
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 	return bids, nil
 }
 
-func (r *bidRepository) GetUserBids(userID string, limit, offset int) ([]models.Bid, error) {
-	query := `
-		SELECT id, tender_id, organization_id, user_id, author_type, description, status, created_at, updated_at
-		FROM bid
-		WHERE user_id = $1
-		ORDER BY description ASC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := r.db.Query(query, userID, limit, offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+func (r *bidRepository) GetBidByID(ctx context.Context, bidID string) (*models.Bid, error) {
+	return r.getBid(ctx, "SELECT "+bidByIDColumns+" FROM bid WHERE id = $1", bidID)
+}
 
-	var bids []models.Bid
-	for rows.Next() {
-		var bid models.Bid
-		if err := rows.Scan(&bid.ID, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.AuthorType, &bid.Description, &bid.Status, &bid.CreatedAt, &bid.UpdatedAt); err != nil {
-			return nil, err
-		}
-		bids = append(bids, bid)
-	}
-	return bids, nil
+func (r *bidRepository) GetBidForUpdate(ctx context.Context, bidID string) (*models.Bid, error) {
+	return r.getBid(ctx, "SELECT "+bidByIDColumns+" FROM bid WHERE id = $1 FOR UPDATE", bidID)
 }
 
-func (r *bidRepository) GetBidByID(bidID string) (*models.Bid, error) {
-	query := `SELECT id, tender_id, organization_id, user_id, description, status, author_type FROM bid WHERE id = $1`
-	row := r.db.QueryRow(query, bidID)
+const bidByIDColumns = "id, tender_id, organization_id, user_id, description, status, author_type, version, created_at"
+
+func (r *bidRepository) getBid(ctx context.Context, query string, bidID string) (*models.Bid, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	row := r.db.QueryRowContext(ctx, query, bidID)
 
 	var bid models.Bid
-	err := row.Scan(&bid.ID, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.Description, &bid.Status, &bid.AuthorType)
+	err := row.Scan(&bid.ID, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.Description, &bid.Status, &bid.AuthorType, &bid.Version, &bid.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, my_errors.ErrBidNotFound
@@ -134,25 +165,26 @@ func (r *bidRepository) GetBidByID(bidID string) (*models.Bid, error) {
 	return &bid, nil
 }
 
-func (r *bidRepository) UpdateBidStatus(bidID string, status models.BidStatus) error {
+// UpdateBidStatus and EditBid only succeed while the stored version still
+// equals the given one and return the bid as it is after the update.
+func (r *bidRepository) UpdateBidStatus(ctx context.Context, bidID string, status models.BidStatus, version int) (*models.Bid, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
         UPDATE bid 
         SET status = $1, updated_at = NOW() 
-        WHERE id = $2
-        RETURNING id
+        WHERE id = $2 AND version = $3
+        RETURNING id, tender_id, organization_id, user_id, author_type, description, status, version, created_at, updated_at
     `
-	var id string
-	err := r.db.QueryRow(query, status, bidID).Scan(&id)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return my_errors.ErrBidNotFound
-		}
-		return err
-	}
-	return nil
+	row := r.db.QueryRowContext(ctx, query, status, bidID, version)
+	return r.scanUpdated(ctx, row, bidID)
 }
 
-func (r *bidRepository) EditBid(bidID string, updates map[string]interface{}) error {
+func (r *bidRepository) EditBid(ctx context.Context, bidID string, updates map[string]interface{}, version int) (*models.Bid, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `UPDATE bid SET `
 	var params []interface{}
 	paramIndex := 1
@@ -166,10 +198,54 @@ func (r *bidRepository) EditBid(bidID string, updates map[string]interface{}) er
 		paramIndex++
 	}
 
-	query += ", updated_at = NOW() WHERE id = $" + fmt.Sprintf("%d", paramIndex)
-	params = append(params, bidID)
+	query += ", updated_at = NOW() WHERE id = $" + fmt.Sprintf("%d", paramIndex) + " AND version = $" + fmt.Sprintf("%d", paramIndex+1)
+	query += " RETURNING id, tender_id, organization_id, user_id, author_type, description, status, version, created_at, updated_at"
+	params = append(params, bidID, version)
+
+	row := r.db.QueryRowContext(ctx, query, params...)
+	return r.scanUpdated(ctx, row, bidID)
+}
+
+func (r *bidRepository) scanUpdated(ctx context.Context, row *sql.Row, bidID string) (*models.Bid, error) {
+	var bid models.Bid
+	err := row.Scan(&bid.ID, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.AuthorType, &bid.Description, &bid.Status, &bid.Version, &bid.CreatedAt, &bid.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			if _, err := r.GetBidByID(ctx, bidID); err != nil {
+				return nil, err
+			}
+			return nil, my_errors.ErrVersionConflict
+		}
+		return nil, err
+	}
+	return &bid, nil
+}
+
+func (r *bidRepository) AddBidFeedback(ctx context.Context, bidID, feedback string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+        INSERT INTO bid_review (bid_id, description, created_at)
+        VALUES ($1, $2, NOW())
+    `
+	_, err := r.db.ExecContext(ctx, query, bidID, feedback)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *bidRepository) AddBidDecision(ctx context.Context, bidID, userID string, decision models.BidDecision) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
 
-	result, err := r.db.Exec(query, params...)
+	query := `
+        INSERT INTO bid_decision (bid_id, user_id, decision, created_at)
+        VALUES ($1, $2, $3, NOW())
+        ON CONFLICT (bid_id, user_id) DO NOTHING
+    `
+	result, err := r.db.ExecContext(ctx, query, bidID, userID, decision)
 	if err != nil {
 		return err
 	}
@@ -179,20 +255,174 @@ func (r *bidRepository) EditBid(bidID string, updates map[string]interface{}) er
 		return err
 	}
 	if rowsAffected == 0 {
-		return my_errors.ErrBidNotFound
+		return my_errors.ErrBidDecisionSubmitted
 	}
 
 	return nil
 }
 
-func (r *bidRepository) AddBidFeedback(bidID, feedback string) error {
+func (r *bidRepository) CountBidDecisions(ctx context.Context, bidID string) (approved, rejected int, err error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
-        INSERT INTO bid_review (bid_id, description, created_at)
-        VALUES ($1, $2, NOW())
+        SELECT
+            COUNT(*) FILTER (WHERE decision = 'Approved'),
+            COUNT(*) FILTER (WHERE decision = 'Rejected')
+        FROM bid_decision
+        WHERE bid_id = $1
     `
-	_, err := r.db.Exec(query, bidID, feedback)
+	err = r.db.QueryRowContext(ctx, query, bidID).Scan(&approved, &rejected)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
-	return nil
+	return approved, rejected, nil
+}
+
+func (r *bidRepository) GetBidHistoryByVersion(ctx context.Context, bidID string, version int) (models.BidHistory, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+        SELECT id, bid_id, tender_id, organization_id, user_id, author_type, description, status, version, updated_at
+        FROM bid_history
+        WHERE bid_id = $1 AND version = $2
+    `
+	var history models.BidHistory
+	err := r.db.QueryRowContext(ctx, query, bidID, version).Scan(
+		&history.ID,
+		&history.BidID,
+		&history.TenderID,
+		&history.OrganizationID,
+		&history.UserID,
+		&history.AuthorType,
+		&history.Description,
+		&history.Status,
+		&history.Version,
+		&history.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return history, my_errors.ErrBidHistoryNotFound
+		}
+		return history, err
+	}
+	return history, nil
+}
+
+func (r *bidRepository) HasUserBidForTender(ctx context.Context, userID, tenderID string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var exists bool
+	query := "SELECT EXISTS(SELECT 1 FROM bid WHERE user_id = $1 AND tender_id = $2)"
+	err := r.db.QueryRowContext(ctx, query, userID, tenderID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *bidRepository) CountTenderBids(ctx context.Context, tenderID string, statuses []models.BidStatus) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	statusValues := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusValues[i] = string(status)
+	}
+
+	var count int
+	query := "SELECT COUNT(1) FROM bid WHERE tender_id = $1 AND status::text = ANY($2)"
+	err := r.db.QueryRowContext(ctx, query, tenderID, pq.Array(statusValues)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetBidReviewsByAuthorID returns reviews newest first. Of options it only
+// honours the creation range and paging, as reviews have no other fields to
+// filter or sort by.
+func (r *bidRepository) GetBidReviewsByAuthorID(ctx context.Context, authorID string, options QueryOptions) ([]models.BidReview, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+        SELECT br.id, br.bid_id, br.description, br.created_at
+        FROM bid_review br
+        JOIN bid b ON br.bid_id = b.id
+        WHERE b.user_id = $1`
+	conditions, args := QueryOptions{CreatedFrom: options.CreatedFrom, CreatedTo: options.CreatedTo}.
+		filterSQL(listColumns{CreatedAt: "br.created_at"}, []interface{}{authorID})
+	page, args := QueryOptions{Limit: options.Limit, Offset: options.Offset, SortBy: SortByCreatedAt, Descending: true}.
+		pageSQL(listColumns{ID: "br.id", CreatedAt: "br.created_at"}, args)
+
+	rows, err := r.db.QueryContext(ctx, query+conditions+page, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []models.BidReview{}
+	for rows.Next() {
+		var review models.BidReview
+		if err := rows.Scan(&review.ID, &review.BidID, &review.Description, &review.CreatedAt); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// SearchBids returns the visible bids whose description matches the
+// web-search style query, best matches first. options filter and page the
+// results but do not sort them.
+func (r *bidRepository) SearchBids(ctx context.Context, query string, visibility BidVisibility, options QueryOptions) ([]models.BidSearchResult, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	statusValues := make([]string, len(visibility.TenderStatuses))
+	for i, status := range visibility.TenderStatuses {
+		statusValues[i] = string(status)
+	}
+
+	sqlQuery := `
+		SELECT id, description, tender_id, organization_id, user_id, author_type, status, version, created_at, updated_at,
+		       ts_rank(search_vector, q) AS rank,
+		       ` + headlineSQL("coalesce(description, '')", 5) + ` AS highlight
+		FROM bid, websearch_to_tsquery('simple', $1) q
+		WHERE search_vector @@ q
+		  AND ((author_type = 'User' AND user_id::text = $2)
+		       OR (author_type = 'Organization' AND organization_id::text = ANY($3))
+		       OR (status::text = ANY($4) AND tender_id IN (SELECT id FROM tender WHERE organization_id::text = ANY($3))))`
+	args := []interface{}{query, visibility.UserID, pq.Array(visibility.OrganizationIDs), pq.Array(statusValues), searchHeadline}
+	conditions, args := options.filterSQL(bidListColumns, args)
+	limit, args := options.limitSQL(args)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery+conditions+" ORDER BY rank DESC, id"+limit, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []models.BidSearchResult{}
+	for rows.Next() {
+		var result models.BidSearchResult
+		bid := &result.Bid
+		if err := rows.Scan(&bid.ID, &bid.Description, &bid.TenderID, &bid.OrganizationID, &bid.UserID, &bid.AuthorType, &bid.Status, &bid.Version, &bid.CreatedAt, &bid.UpdatedAt, &result.Rank, &result.Highlight); err != nil {
+			return nil, err
+		}
+		result.Highlight = escapeHighlight(result.Highlight)
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
 }
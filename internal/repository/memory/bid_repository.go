@@ -0,0 +1,314 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type bidRepository struct {
+	store *Store
+}
+
+func NewBidRepository(store *Store) repository.BidRepository {
+	return &bidRepository{store: store}
+}
+
+func (r *bidRepository) CreateBid(ctx context.Context, bid *models.Bid) (*models.Bid, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := timestamp(time.Now().UTC())
+	bid.ID = uuid.NewString()
+	bid.Version = 1
+	bid.CreatedAt = now
+	bid.UpdatedAt = now
+	r.store.bids[bid.ID] = *bid
+	r.store.bidOrder = append(r.store.bidOrder, bid.ID)
+	return bid, nil
+}
+
+func (r *bidRepository) GetBidsByTenderID(ctx context.Context, tenderID string, statuses []models.BidStatus, options repository.QueryOptions) ([]models.Bid, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	bids := r.filter(func(bid models.Bid) bool {
+		return bid.TenderID == tenderID && containsBidStatus(statuses, bid.Status)
+	})
+	return applyOptions(bids, options, bidFields), nil
+}
+
+func (r *bidRepository) GetBidsByUserID(ctx context.Context, userID string, options repository.QueryOptions) ([]models.Bid, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	bids := r.filter(func(bid models.Bid) bool {
+		return bid.UserID == userID
+	})
+	return applyOptions(bids, options, bidFields), nil
+}
+
+func (r *bidRepository) SearchBids(ctx context.Context, query string, visibility repository.BidVisibility, options repository.QueryOptions) ([]models.BidSearchResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	terms := searchTerms(query)
+	var results []models.BidSearchResult
+	for _, bid := range r.filter(func(bid models.Bid) bool { return r.visible(bid, visibility) }) {
+		rank, highlight, ok := matchText(terms, searchField{bid.Description, 1})
+		if ok {
+			results = append(results, models.BidSearchResult{Bid: bid, Rank: rank, Highlight: highlight})
+		}
+	}
+	return applySearchOptions(results, options, func(result models.BidSearchResult) listFields {
+		return bidFields(result.Bid)
+	}, func(result models.BidSearchResult) float64 {
+		return result.Rank
+	}), nil
+}
+
+// visible mirrors the visibility condition of the SQL search. The caller must
+// hold the lock.
+func (r *bidRepository) visible(bid models.Bid, visibility repository.BidVisibility) bool {
+	switch {
+	case bid.AuthorType == models.BidAuthorTypeUser && bid.UserID == visibility.UserID:
+		return true
+	case bid.AuthorType == models.BidAuthorTypeOrganization && containsString(visibility.OrganizationIDs, bid.OrganizationID):
+		return true
+	}
+	tender, ok := r.store.tenders[bid.TenderID]
+	return ok && containsBidStatus(visibility.TenderStatuses, bid.Status) && containsString(visibility.OrganizationIDs, tender.OrganizationID)
+}
+
+func bidFields(bid models.Bid) listFields {
+	createdAt, _ := time.Parse(time.RFC3339Nano, bid.CreatedAt)
+	updatedAt, _ := time.Parse(time.RFC3339Nano, bid.UpdatedAt)
+	return listFields{
+		id:             bid.ID,
+		name:           bid.Description,
+		status:         string(bid.Status),
+		organizationID: bid.OrganizationID,
+		createdAt:      createdAt,
+		updatedAt:      updatedAt,
+	}
+}
+
+func (r *bidRepository) GetBidByID(ctx context.Context, bidID string) (*models.Bid, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	bid, ok := r.store.bids[bidID]
+	if !ok {
+		return nil, my_errors.ErrBidNotFound
+	}
+	return &bid, nil
+}
+
+// GetBidForUpdate needs no lock of its own: a unit of work holds the store's
+// lock throughout.
+func (r *bidRepository) GetBidForUpdate(ctx context.Context, bidID string) (*models.Bid, error) {
+	return r.GetBidByID(ctx, bidID)
+}
+
+func (r *bidRepository) UpdateBidStatus(ctx context.Context, bidID string, status models.BidStatus, version int) (*models.Bid, error) {
+	return r.update(bidID, version, func(bid *models.Bid) error {
+		bid.Status = status
+		return nil
+	})
+}
+
+func (r *bidRepository) EditBid(ctx context.Context, bidID string, updates map[string]interface{}, version int) (*models.Bid, error) {
+	return r.update(bidID, version, func(bid *models.Bid) error {
+		for field, value := range updates {
+			switch field {
+			case "description":
+				description, ok := value.(string)
+				if !ok {
+					return fmt.Errorf("invalid value for bid field %q", field)
+				}
+				bid.Description = description
+			default:
+				return fmt.Errorf("unknown bid field %q", field)
+			}
+		}
+		return nil
+	})
+}
+
+// update mirrors the save_bid_version trigger: the previous state goes to the
+// history and the version is incremented. Like the SQL implementation it only
+// applies while the stored version equals the expected one.
+func (r *bidRepository) update(bidID string, version int, apply func(*models.Bid) error) (*models.Bid, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	bid, ok := r.store.bids[bidID]
+	if !ok {
+		return nil, my_errors.ErrBidNotFound
+	}
+	if bid.Version != version {
+		return nil, my_errors.ErrVersionConflict
+	}
+
+	previous := bid
+	if err := apply(&bid); err != nil {
+		return nil, err
+	}
+
+	r.store.bidHistory[bidID] = append(r.store.bidHistory[bidID], models.BidHistory{
+		ID:             uuid.NewString(),
+		BidID:          previous.ID,
+		TenderID:       previous.TenderID,
+		OrganizationID: previous.OrganizationID,
+		UserID:         previous.UserID,
+		AuthorType:     previous.AuthorType,
+		Description:    previous.Description,
+		Status:         previous.Status,
+		Version:        previous.Version,
+		UpdatedAt:      previous.UpdatedAt,
+	})
+
+	bid.Version++
+	bid.UpdatedAt = timestamp(time.Now().UTC())
+	r.store.bids[bidID] = bid
+	return &bid, nil
+}
+
+func (r *bidRepository) AddBidFeedback(ctx context.Context, bidID, feedback string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.reviews = append(r.store.reviews, models.BidReview{
+		ID:          uuid.NewString(),
+		BidID:       bidID,
+		Description: feedback,
+		CreatedAt:   time.Now().UTC(),
+	})
+	return nil
+}
+
+func (r *bidRepository) AddBidDecision(ctx context.Context, bidID, userID string, decision models.BidDecision) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.decisions[bidID] == nil {
+		r.store.decisions[bidID] = make(map[string]models.BidDecision)
+	}
+	if _, exists := r.store.decisions[bidID][userID]; exists {
+		return my_errors.ErrBidDecisionSubmitted
+	}
+	r.store.decisions[bidID][userID] = decision
+	return nil
+}
+
+func (r *bidRepository) CountBidDecisions(ctx context.Context, bidID string) (approved, rejected int, err error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, decision := range r.store.decisions[bidID] {
+		switch decision {
+		case models.BidDecisionApproved:
+			approved++
+		case models.BidDecisionRejected:
+			rejected++
+		}
+	}
+	return approved, rejected, nil
+}
+
+func (r *bidRepository) GetBidHistoryByVersion(ctx context.Context, bidID string, version int) (models.BidHistory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, history := range r.store.bidHistory[bidID] {
+		if history.Version == version {
+			return history, nil
+		}
+	}
+	return models.BidHistory{}, my_errors.ErrBidHistoryNotFound
+}
+
+func (r *bidRepository) HasUserBidForTender(ctx context.Context, userID, tenderID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, bid := range r.store.bids {
+		if bid.UserID == userID && bid.TenderID == tenderID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *bidRepository) CountTenderBids(ctx context.Context, tenderID string, statuses []models.BidStatus) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	bids := r.filter(func(bid models.Bid) bool {
+		return bid.TenderID == tenderID && containsBidStatus(statuses, bid.Status)
+	})
+	return len(bids), nil
+}
+
+func (r *bidRepository) GetBidReviewsByAuthorID(ctx context.Context, authorID string, options repository.QueryOptions) ([]models.BidReview, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	reviews := []models.BidReview{}
+	for _, review := range r.store.reviews {
+		if bid, ok := r.store.bids[review.BidID]; ok && bid.UserID == authorID {
+			reviews = append(reviews, review)
+		}
+	}
+	reviews = applyOptions(reviews, repository.QueryOptions{
+		Limit:       options.Limit,
+		Offset:      options.Offset,
+		CreatedFrom: options.CreatedFrom,
+		CreatedTo:   options.CreatedTo,
+		SortBy:      repository.SortByCreatedAt,
+		Descending:  true,
+	}, func(review models.BidReview) listFields {
+		return listFields{id: review.ID, createdAt: review.CreatedAt}
+	})
+	if reviews == nil {
+		reviews = []models.BidReview{}
+	}
+	return reviews, nil
+}
+
+// filter returns matching bids in creation order. The caller must hold the lock.
+func (r *bidRepository) filter(match func(models.Bid) bool) []models.Bid {
+	var bids []models.Bid
+	for _, id := range r.store.bidOrder {
+		if bid := r.store.bids[id]; match(bid) {
+			bids = append(bids, bid)
+		}
+	}
+	return bids
+}
+
+func containsBidStatus(statuses []models.BidStatus, status models.BidStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
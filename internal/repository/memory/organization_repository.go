@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type organizationRepository struct {
+	store *Store
+}
+
+func NewOrganizationRepository(store *Store) repository.OrganizationRepository {
+	return &organizationRepository{store: store}
+}
+
+func (r *organizationRepository) CreateOrganization(ctx context.Context, organization models.Organization, responsibleID string) (models.Organization, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	organization.ID = uuid.NewString()
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+	r.store.organizations[organization.ID] = organization
+	r.store.responsibles[organization.ID] = map[string]bool{responsibleID: true}
+	return organization, nil
+}
+
+func (r *organizationRepository) GetOrganizations(ctx context.Context) ([]models.Organization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	organizations := []models.Organization{}
+	for _, organization := range r.store.organizations {
+		organizations = append(organizations, organization)
+	}
+	sortOrganizations(organizations)
+	return organizations, nil
+}
+
+func (r *organizationRepository) GetOrganizationByID(ctx context.Context, organizationID string) (models.Organization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	organization, ok := r.store.organizations[organizationID]
+	if !ok {
+		return models.Organization{}, my_errors.ErrOrganizationNotFound
+	}
+	return organization, nil
+}
+
+func (r *organizationRepository) GetUserOrganizations(ctx context.Context, userID string) ([]models.Organization, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	organizations := []models.Organization{}
+	for organizationID, users := range r.store.responsibles {
+		organization, ok := r.store.organizations[organizationID]
+		if ok && users[userID] {
+			organizations = append(organizations, organization)
+		}
+	}
+	sortOrganizations(organizations)
+	return organizations, nil
+}
+
+func (r *organizationRepository) GetResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := []models.User{}
+	for userID := range r.store.responsibles[organizationID] {
+		if user, ok := r.store.employees[userID]; ok {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+	return users, nil
+}
+
+func (r *organizationRepository) IsResponsible(ctx context.Context, userID, organizationID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.isResponsible(userID, organizationID), nil
+}
+
+func (r *organizationRepository) AddResponsible(ctx context.Context, organizationID, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.isResponsible(userID, organizationID) {
+		return my_errors.ErrResponsibleExists
+	}
+	if r.store.responsibles[organizationID] == nil {
+		r.store.responsibles[organizationID] = make(map[string]bool)
+	}
+	r.store.responsibles[organizationID][userID] = true
+	return nil
+}
+
+func (r *organizationRepository) RemoveResponsible(ctx context.Context, organizationID, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.organizations[organizationID]; !ok {
+		return my_errors.ErrOrganizationNotFound
+	}
+	if !r.store.isResponsible(userID, organizationID) {
+		return my_errors.ErrResponsibleNotFound
+	}
+	if len(r.store.responsibles[organizationID]) <= 1 {
+		return my_errors.ErrLastResponsible
+	}
+	delete(r.store.responsibles[organizationID], userID)
+	return nil
+}
+
+func sortOrganizations(organizations []models.Organization) {
+	sort.Slice(organizations, func(i, j int) bool {
+		if organizations[i].Name != organizations[j].Name {
+			return organizations[i].Name < organizations[j].Name
+		}
+		return organizations[i].ID < organizations[j].ID
+	})
+}
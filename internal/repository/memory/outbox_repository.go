@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+)
+
+type outboxRepository struct {
+	store *Store
+}
+
+func NewOutboxRepository(store *Store) repository.OutboxRepository {
+	return &outboxRepository{store: store}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events ...event.Event) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		r.store.outbox = append(r.store.outbox, models.OutboxEntry{
+			ID:            int64(len(r.store.outbox) + 1),
+			EventType:     string(e.Type()),
+			SubjectID:     e.SubjectID(),
+			Payload:       payload,
+			Status:        models.OutboxPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		})
+	}
+	return nil
+}
+
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEntry, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	claimed := []models.OutboxEntry{}
+	for i := range r.store.outbox {
+		entry := &r.store.outbox[i]
+		if len(claimed) == limit {
+			break
+		}
+		if entry.Status != models.OutboxPending || entry.NextAttemptAt.After(now) {
+			continue
+		}
+		entry.Attempts++
+		entry.NextAttemptAt = now.Add(lease)
+		claimed = append(claimed, *entry)
+	}
+	return claimed, nil
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	return r.update(id, func(entry *models.OutboxEntry) {
+		entry.Status = models.OutboxSent
+		entry.SentAt = &sentAt
+		entry.LastError = ""
+	})
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, final bool) error {
+	return r.update(id, func(entry *models.OutboxEntry) {
+		if final {
+			entry.Status = models.OutboxFailed
+		}
+		entry.LastError = reason
+		entry.NextAttemptAt = nextAttemptAt
+	})
+}
+
+func (r *outboxRepository) update(id int64, apply func(*models.OutboxEntry)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// IDs are positions in the outbox, which only ever grows.
+	if id >= 1 && id <= int64(len(r.store.outbox)) {
+		apply(&r.store.outbox[id-1])
+	}
+	return nil
+}
+
+func (r *outboxRepository) ListOutbox(ctx context.Context, statuses []models.OutboxStatus, limit, offset int) ([]models.OutboxEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	entries := []models.OutboxEntry{}
+	for _, entry := range r.store.outbox {
+		if len(statuses) == 0 || containsOutboxStatus(statuses, entry.Status) {
+			entries = append(entries, entry)
+		}
+	}
+	return paginate(entries, limit, offset), nil
+}
+
+func containsOutboxStatus(statuses []models.OutboxStatus, status models.OutboxStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"tender-service/internal/repository"
+)
+
+// listFields are the values of one item that repository.QueryOptions can
+// filter and sort by. Empty fields mean the item has no such field, except
+// serviceType, which is nil then: tenders may have an empty service type,
+// and filters by service type must not match it.
+type listFields struct {
+	id             string
+	name           string
+	status         string
+	serviceType    *string
+	organizationID string
+	createdAt      time.Time
+	updatedAt      time.Time
+}
+
+func matchesOptions(options repository.QueryOptions, item listFields) bool {
+	if len(options.Statuses) > 0 && !containsString(options.Statuses, item.status) {
+		return false
+	}
+	if len(options.ServiceTypes) > 0 && item.serviceType != nil && !containsString(options.ServiceTypes, *item.serviceType) {
+		return false
+	}
+	if len(options.OrganizationIDs) > 0 && !containsString(options.OrganizationIDs, item.organizationID) {
+		return false
+	}
+	if !options.CreatedFrom.IsZero() && item.createdAt.Before(options.CreatedFrom) {
+		return false
+	}
+	if !options.CreatedTo.IsZero() && !item.createdAt.Before(options.CreatedTo) {
+		return false
+	}
+	if options.After != nil && !pastCursor(*options.After, item, options.Descending) {
+		return false
+	}
+	return true
+}
+
+// pastCursor reports whether item comes after the cursor in (createdAt, id)
+// order, or before it when descending.
+func pastCursor(cursor repository.Cursor, item listFields, descending bool) bool {
+	after := item.createdAt.After(cursor.CreatedAt) ||
+		(item.createdAt.Equal(cursor.CreatedAt) && item.id > cursor.ID)
+	before := item.createdAt.Before(cursor.CreatedAt) ||
+		(item.createdAt.Equal(cursor.CreatedAt) && item.id < cursor.ID)
+	if descending {
+		return before
+	}
+	return after
+}
+
+// applyOptions filters, sorts and pages items the way the SQL repositories do.
+func applyOptions[T any](items []T, options repository.QueryOptions, fields func(T) listFields) []T {
+	var matched []T
+	for _, item := range items {
+		if matchesOptions(options, fields(item)) {
+			matched = append(matched, item)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := fields(matched[i]), fields(matched[j])
+		if options.Descending {
+			a, b = b, a
+		}
+		switch {
+		case options.SortBy == repository.SortByCreatedAt && !a.createdAt.Equal(b.createdAt):
+			return a.createdAt.Before(b.createdAt)
+		case options.SortBy == repository.SortByUpdatedAt && !a.updatedAt.Equal(b.updatedAt):
+			return a.updatedAt.Before(b.updatedAt)
+		case options.SortBy != repository.SortByCreatedAt && options.SortBy != repository.SortByUpdatedAt && a.name != b.name:
+			return a.name < b.name
+		}
+		return a.id < b.id
+	})
+
+	limit := options.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	return paginate(matched, limit, options.Offset)
+}
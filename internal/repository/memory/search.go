@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"tender-service/internal/repository"
+)
+
+// searchField is a text an item is searched by. Weight plays the role of the
+// tsvector weights of the SQL repositories: a hit in a name counts more than
+// one in a description.
+type searchField struct {
+	text   string
+	weight float64
+}
+
+// searchTerms splits a query into lowercase words. Unlike
+// websearch_to_tsquery it has no phrases or negation: every word must occur.
+func searchTerms(query string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(query), isNotWordRune) {
+		if word != "or" {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+func isNotWordRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// matchText reports whether all terms occur in fields as whole words. The rank
+// is the weighted number of hits and the highlight is the HTML-escaped text of
+// all fields with the hits wrapped in <b></b>.
+func matchText(terms []string, fields ...searchField) (rank float64, highlight string, ok bool) {
+	if len(terms) == 0 {
+		return 0, "", false
+	}
+	found := make(map[string]bool, len(terms))
+	for _, term := range terms {
+		found[term] = false
+	}
+
+	var b strings.Builder
+	for _, field := range fields {
+		if field.text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+
+		text := field.text
+		for len(text) > 0 {
+			start := strings.IndexFunc(text, func(r rune) bool { return !isNotWordRune(r) })
+			if start < 0 {
+				b.WriteString(html.EscapeString(text))
+				break
+			}
+			b.WriteString(html.EscapeString(text[:start]))
+			text = text[start:]
+			end := strings.IndexFunc(text, isNotWordRune)
+			if end < 0 {
+				end = len(text)
+			}
+			word := text[:end]
+			text = text[end:]
+
+			if _, isTerm := found[strings.ToLower(word)]; isTerm {
+				found[strings.ToLower(word)] = true
+				rank += field.weight
+				b.WriteString("<b>" + word + "</b>")
+			} else {
+				b.WriteString(word)
+			}
+		}
+	}
+
+	for _, hit := range found {
+		if !hit {
+			return 0, "", false
+		}
+	}
+	return rank, b.String(), true
+}
+
+// applySearchOptions filters and pages search results like applyOptions but
+// keeps them ordered by rank, best first, then by ID.
+func applySearchOptions[T any](items []T, options repository.QueryOptions, fields func(T) listFields, rank func(T) float64) []T {
+	matched := []T{}
+	for _, item := range items {
+		if matchesOptions(options, fields(item)) {
+			matched = append(matched, item)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if rank(matched[i]) != rank(matched[j]) {
+			return rank(matched[i]) > rank(matched[j])
+		}
+		return fields(matched[i]).id < fields(matched[j]).id
+	})
+
+	limit := options.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	return paginate(matched, limit, options.Offset)
+}
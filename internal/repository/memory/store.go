@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"tender-service/internal/auth"
+	"tender-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Store keeps all in-memory data behind a single lock so that repositories
+// sharing it see a consistent view, the same way they would share a database.
+type Store struct {
+	mu rwLocker
+	*storeData
+}
+
+type rwLocker interface {
+	Lock()
+	Unlock()
+	RLock()
+	RUnlock()
+}
+
+// storeData is what a Store holds. Every field must be copied by clone.
+type storeData struct {
+	employees map[string]models.User
+	passwords map[string]string
+
+	organizations map[string]models.Organization
+	responsibles  map[string]map[string]bool
+
+	tenders       map[string]models.Tender
+	tenderHistory map[string][]models.TenderHistory
+
+	bids       map[string]models.Bid
+	bidOrder   []string
+	bidHistory map[string][]models.BidHistory
+	reviews    []models.BidReview
+	decisions  map[string]map[string]models.BidDecision
+
+	outbox []models.OutboxEntry
+
+	webhooks          map[string]models.WebhookSubscription
+	webhookDeliveries []models.WebhookDelivery
+}
+
+func NewStore() *Store {
+	return &Store{mu: &sync.RWMutex{}, storeData: &storeData{
+		employees:     make(map[string]models.User),
+		passwords:     make(map[string]string),
+		organizations: make(map[string]models.Organization),
+		responsibles:  make(map[string]map[string]bool),
+		tenders:       make(map[string]models.Tender),
+		tenderHistory: make(map[string][]models.TenderHistory),
+		bids:          make(map[string]models.Bid),
+		bidHistory:    make(map[string][]models.BidHistory),
+		decisions:     make(map[string]map[string]models.BidDecision),
+		webhooks:      make(map[string]models.WebhookSubscription),
+	}}
+}
+
+// clone deeply copies the data, so that changes to one copy leave the other
+// as it was.
+func (d *storeData) clone() *storeData {
+	return &storeData{
+		employees:         cloneMap(d.employees),
+		passwords:         cloneMap(d.passwords),
+		organizations:     cloneMap(d.organizations),
+		responsibles:      cloneNestedMap(d.responsibles),
+		tenders:           cloneMap(d.tenders),
+		tenderHistory:     cloneSliceMap(d.tenderHistory),
+		bids:              cloneMap(d.bids),
+		bidOrder:          append([]string(nil), d.bidOrder...),
+		bidHistory:        cloneSliceMap(d.bidHistory),
+		reviews:           append([]models.BidReview(nil), d.reviews...),
+		decisions:         cloneNestedMap(d.decisions),
+		outbox:            append([]models.OutboxEntry(nil), d.outbox...),
+		webhooks:          cloneMap(d.webhooks),
+		webhookDeliveries: append([]models.WebhookDelivery(nil), d.webhookDeliveries...),
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	clone := make(map[K]V, len(m))
+	for key, value := range m {
+		clone[key] = value
+	}
+	return clone
+}
+
+func cloneNestedMap[K, L comparable, V any](m map[K]map[L]V) map[K]map[L]V {
+	clone := make(map[K]map[L]V, len(m))
+	for key, inner := range m {
+		clone[key] = cloneMap(inner)
+	}
+	return clone
+}
+
+func cloneSliceMap[K comparable, V any](m map[K][]V) map[K][]V {
+	clone := make(map[K][]V, len(m))
+	for key, values := range m {
+		clone[key] = append([]V(nil), values...)
+	}
+	return clone
+}
+
+// AddEmployee stores an active employee, generating an ID when none is given.
+func (s *Store) AddEmployee(user models.User) models.User {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = uuid.NewString()
+	}
+	user.Active = true
+	now := time.Now().UTC()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+	s.employees[user.ID] = user
+	return user
+}
+
+// SetPassword lets the employee obtain tokens with the given password.
+func (s *Store) SetPassword(userID, password string) error {
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.passwords[userID] = hash
+	return nil
+}
+
+// AddOrganization stores an organization, generating an ID when none is given.
+func (s *Store) AddOrganization(organization models.Organization) models.Organization {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if organization.ID == "" {
+		organization.ID = uuid.NewString()
+	}
+	now := time.Now().UTC()
+	if organization.CreatedAt.IsZero() {
+		organization.CreatedAt = now
+	}
+	if organization.UpdatedAt.IsZero() {
+		organization.UpdatedAt = now
+	}
+	s.organizations[organization.ID] = organization
+	return organization
+}
+
+// AddOrganizationResponsible makes the user a responsible of the organization.
+func (s *Store) AddOrganizationResponsible(organizationID, userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.responsibles[organizationID] == nil {
+		s.responsibles[organizationID] = make(map[string]bool)
+	}
+	s.responsibles[organizationID][userID] = true
+}
+
+type seed struct {
+	Employees []struct {
+		models.User
+		Password string `json:"password"`
+	} `json:"employees"`
+	Organizations            []models.Organization `json:"organizations"`
+	OrganizationResponsibles []struct {
+		OrganizationID string `json:"organizationId"`
+		UserID         string `json:"userId"`
+	} `json:"organizationResponsibles"`
+}
+
+// LoadSeed fills the store with employees and organization responsibles from
+// a JSON file, so that a memory-backed server has someone to act as.
+func (s *Store) LoadSeed(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var content seed
+	if err := json.Unmarshal(data, &content); err != nil {
+		return err
+	}
+
+	for _, employee := range content.Employees {
+		user := s.AddEmployee(employee.User)
+		if employee.Password != "" {
+			if err := s.SetPassword(user.ID, employee.Password); err != nil {
+				return err
+			}
+		}
+	}
+	for _, organization := range content.Organizations {
+		s.AddOrganization(organization)
+	}
+	for _, responsible := range content.OrganizationResponsibles {
+		s.AddOrganizationResponsible(responsible.OrganizationID, responsible.UserID)
+	}
+	return nil
+}
+
+func (s *Store) isResponsible(userID, organizationID string) bool {
+	return s.responsibles[organizationID][userID]
+}
+
+func timestamp(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
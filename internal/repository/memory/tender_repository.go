@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type tenderRepository struct {
+	store *Store
+}
+
+func NewTenderRepository(store *Store) repository.TenderRepository {
+	return &tenderRepository{store: store}
+}
+
+func (r *tenderRepository) GetTenders(ctx context.Context, filter repository.TenderFilter, options repository.QueryOptions) ([]models.Tender, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tenders []models.Tender
+	for _, tender := range r.store.tenders {
+		if !containsStatus(filter.PublicStatuses, tender.Status) && !containsString(filter.OrganizationIDs, tender.OrganizationID) {
+			continue
+		}
+		tenders = append(tenders, tender)
+	}
+	return applyOptions(tenders, options, tenderFields), nil
+}
+
+func (r *tenderRepository) SearchTenders(ctx context.Context, query string, filter repository.TenderFilter, options repository.QueryOptions) ([]models.TenderSearchResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	terms := searchTerms(query)
+	var results []models.TenderSearchResult
+	for _, tender := range r.store.tenders {
+		if !containsStatus(filter.PublicStatuses, tender.Status) && !containsString(filter.OrganizationIDs, tender.OrganizationID) {
+			continue
+		}
+		rank, highlight, ok := matchText(terms, searchField{tender.Name, 1}, searchField{tender.Description, 0.4})
+		if ok {
+			results = append(results, models.TenderSearchResult{Tender: tender, Rank: rank, Highlight: highlight})
+		}
+	}
+	return applySearchOptions(results, options, func(result models.TenderSearchResult) listFields {
+		return tenderFields(result.Tender)
+	}, func(result models.TenderSearchResult) float64 {
+		return result.Rank
+	}), nil
+}
+
+func tenderFields(tender models.Tender) listFields {
+	return listFields{
+		id:             tender.ID,
+		name:           tender.Name,
+		status:         string(tender.Status),
+		serviceType:    &tender.ServiceType,
+		organizationID: tender.OrganizationID,
+		createdAt:      tender.CreatedAt,
+		updatedAt:      tender.UpdatedAt,
+	}
+}
+
+func (r *tenderRepository) CreateTender(ctx context.Context, tender models.Tender) (models.Tender, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	tender.ID = uuid.NewString()
+	tender.Version = 1
+	tender.CreatedAt = now
+	tender.UpdatedAt = now
+	r.store.tenders[tender.ID] = tender
+	return tender, nil
+}
+
+func (r *tenderRepository) GetTenderByID(ctx context.Context, tenderId string) (models.Tender, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tender, ok := r.store.tenders[tenderId]
+	if !ok {
+		return models.Tender{}, my_errors.ErrTenderNotFound
+	}
+	return tender, nil
+}
+
+// GetTenderForShare and GetTenderForUpdate need no lock of their own: a unit of work holds the
+// store's lock throughout.
+func (r *tenderRepository) GetTenderForShare(ctx context.Context, tenderId string) (models.Tender, error) {
+	return r.GetTenderByID(ctx, tenderId)
+}
+
+func (r *tenderRepository) GetTenderForUpdate(ctx context.Context, tenderId string) (models.Tender, error) {
+	return r.GetTenderByID(ctx, tenderId)
+}
+
+func (r *tenderRepository) UpdateTenderStatus(ctx context.Context, tender models.Tender) (models.Tender, error) {
+	return r.update(tender.ID, tender.Version, func(stored *models.Tender) {
+		stored.Status = tender.Status
+	})
+}
+
+func (r *tenderRepository) UpdateTender(ctx context.Context, tender models.Tender) (models.Tender, error) {
+	return r.update(tender.ID, tender.Version, func(stored *models.Tender) {
+		stored.Name = tender.Name
+		stored.Description = tender.Description
+		stored.ServiceType = tender.ServiceType
+		stored.SubmissionDeadline = tender.SubmissionDeadline
+		stored.DecisionDeadline = tender.DecisionDeadline
+	})
+}
+
+// update mirrors the save_tender_version trigger: the previous state goes to
+// the history and the version is incremented. Like the SQL implementation it
+// only applies while the stored version equals the expected one.
+func (r *tenderRepository) update(tenderId string, version int, apply func(*models.Tender)) (models.Tender, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tender, ok := r.store.tenders[tenderId]
+	if !ok {
+		return models.Tender{}, my_errors.ErrTenderNotFound
+	}
+	if tender.Version != version {
+		return models.Tender{}, my_errors.ErrVersionConflict
+	}
+	return r.save(tender, apply), nil
+}
+
+// save records the tender's current state in the history and stores it with
+// apply's changes under the next version. The caller must hold the write lock.
+func (r *tenderRepository) save(tender models.Tender, apply func(*models.Tender)) models.Tender {
+	r.store.tenderHistory[tender.ID] = append(r.store.tenderHistory[tender.ID], models.TenderHistory{
+		ID:                 uuid.NewString(),
+		TenderID:           tender.ID,
+		Name:               tender.Name,
+		Description:        tender.Description,
+		ServiceType:        tender.ServiceType,
+		Status:             tender.Status,
+		OrganizationID:     tender.OrganizationID,
+		CreatorID:          tender.CreatorID,
+		Version:            tender.Version,
+		UpdatedAt:          tender.UpdatedAt,
+		SubmissionDeadline: tender.SubmissionDeadline,
+		DecisionDeadline:   tender.DecisionDeadline,
+	})
+
+	apply(&tender)
+	tender.Version++
+	tender.UpdatedAt = time.Now().UTC()
+	r.store.tenders[tender.ID] = tender
+	return tender
+}
+
+func (r *tenderRepository) CloseExpiredTenders(ctx context.Context, now time.Time, limit int) ([]models.Tender, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired []models.Tender
+	for _, tender := range r.store.tenders {
+		if expiresAt := tender.ExpiresAt(); tender.Status == models.Published && expiresAt != nil && !expiresAt.After(now) {
+			expired = append(expired, tender)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].ExpiresAt().Before(*expired[j].ExpiresAt())
+	})
+	if limit > 0 && len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	closed := make([]models.Tender, 0, len(expired))
+	for _, tender := range expired {
+		closed = append(closed, r.save(tender, func(stored *models.Tender) {
+			stored.Status = models.Closed
+		}))
+	}
+	return closed, nil
+}
+
+func (r *tenderRepository) IsUserResponsibleForOrganization(ctx context.Context, userId, organizationId string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.isResponsible(userId, organizationId), nil
+}
+
+func (r *tenderRepository) GetUserOrganizationIDs(ctx context.Context, userId string) ([]string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var organizationIDs []string
+	for organizationID, users := range r.store.responsibles {
+		if users[userId] {
+			organizationIDs = append(organizationIDs, organizationID)
+		}
+	}
+	sort.Strings(organizationIDs)
+	return organizationIDs, nil
+}
+
+func (r *tenderRepository) GetTenderHistoryVersion(ctx context.Context, tenderId string, version int) (models.TenderHistory, error) {
+	return r.GetTenderHistoryByVersion(ctx, tenderId, version)
+}
+
+func (r *tenderRepository) GetTenderHistoryByVersion(ctx context.Context, tenderId string, version int) (models.TenderHistory, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, history := range r.store.tenderHistory[tenderId] {
+		if history.Version == version {
+			return history, nil
+		}
+	}
+	return models.TenderHistory{}, my_errors.ErrTenderHistoryNotFound
+}
+
+func containsStatus(statuses []models.TenderStatus, status models.TenderStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+
+	"tender-service/internal/repository"
+)
+
+type unitOfWork struct {
+	store *Store
+}
+
+// NewUnitOfWork returns a UnitOfWork over the store. A unit of work holds the
+// store's lock from start to end, so units run one at a time and nothing sees
+// their changes before they commit; a rollback restores the data as it was
+// at the start. Since the lock is held, fn must only use the repositories it
+// is given: any other repository of the store blocks until fn returns.
+func NewUnitOfWork(store *Store) repository.UnitOfWork {
+	return &unitOfWork{store: store}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos repository.Repositories) error) (err error) {
+	u.store.mu.Lock()
+	defer u.store.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	snapshot := u.store.storeData.clone()
+	defer func() {
+		if p := recover(); p != nil {
+			*u.store.storeData = *snapshot
+			panic(p)
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			*u.store.storeData = *snapshot
+		}
+	}()
+
+	// The repositories share the store's data but not its lock, which the
+	// unit of work already holds.
+	held := &Store{mu: heldLock{}, storeData: u.store.storeData}
+	return fn(ctx, repository.Repositories{
+		Tenders: NewTenderRepository(held),
+		Bids:    NewBidRepository(held),
+		Users:   NewUserRepository(held),
+		Outbox:  NewOutboxRepository(held),
+	})
+}
+
+// heldLock stands in for a lock the unit of work already holds.
+type heldLock struct{}
+
+func (heldLock) Lock()    {}
+func (heldLock) Unlock()  {}
+func (heldLock) RLock()   {}
+func (heldLock) RUnlock() {}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_CommitsAndRollsBack(t *testing.T) {
+	store := NewStore()
+	tenders := NewTenderRepository(store)
+	outbox := NewOutboxRepository(store)
+	uow := NewUnitOfWork(store)
+
+	tender, err := tenders.CreateTender(context.Background(), models.Tender{Name: "Delivery", Status: models.Created})
+	require.NoError(t, err)
+
+	publish := func(ctx context.Context, repos repository.Repositories) error {
+		tender.Status = models.Published
+		published, err := repos.Tenders.UpdateTenderStatus(ctx, tender)
+		if err != nil {
+			return err
+		}
+		return repos.Outbox.Append(ctx, event.TenderPublished{Tender: published})
+	}
+	assertUnchanged := func() {
+		t.Helper()
+		stored, err := tenders.GetTenderByID(context.Background(), tender.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.Created, stored.Status)
+		assert.Equal(t, 1, stored.Version)
+		entries, err := outbox.ListOutbox(context.Background(), nil, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	}
+
+	failure := errors.New("step failed")
+	err = uow.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+		if err := publish(ctx, repos); err != nil {
+			return err
+		}
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assertUnchanged()
+
+	assert.Panics(t, func() {
+		uow.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+			publish(ctx, repos)
+			panic("step panicked")
+		})
+	})
+	assertUnchanged()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = uow.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		err := publish(ctx, repos)
+		cancel()
+		return err
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assertUnchanged()
+
+	require.NoError(t, uow.Do(context.Background(), publish))
+	stored, err := tenders.GetTenderByID(context.Background(), tender.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.Published, stored.Status)
+	entries, err := outbox.ListOutbox(context.Background(), nil, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestOnCommit_ObservesCommittedEventsOnly(t *testing.T) {
+	store := NewStore()
+	var observed []event.Event
+	uow := repository.OnCommit(NewUnitOfWork(store), func(ctx context.Context, events []event.Event) {
+		observed = append(observed, events...)
+	})
+	created := event.TenderCreated{Tender: models.Tender{ID: "tender"}}
+
+	err := uow.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+		require.NoError(t, repos.Outbox.Append(ctx, created))
+		return errors.New("boom")
+	})
+	require.Error(t, err)
+	assert.Empty(t, observed)
+
+	require.NoError(t, uow.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+		return repos.Outbox.Append(ctx, created)
+	}))
+	assert.Equal(t, []event.Event{created}, observed)
+}
@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &userRepository{store: store}
+}
+
+func (r *userRepository) FindUserIDByUsername(ctx context.Context, username string) (string, error) {
+	user, err := r.GetUserByUsername(ctx, username)
+	if err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
+func (r *userRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.employees[userID]
+	if !ok || !user.Active {
+		return nil, my_errors.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *userRepository) CheckUserPermission(ctx context.Context, userID, organizationID string) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.isResponsible(userID, organizationID), nil
+}
+
+func (r *userRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.employees {
+		if user.Username == username && user.Active {
+			return &user, nil
+		}
+	}
+	return nil, my_errors.ErrUserNotFound
+}
+
+func (r *userRepository) CountOrganizationResponsibles(ctx context.Context, organizationID string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for userID := range r.store.responsibles[organizationID] {
+		if r.store.employees[userID].Active {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *userRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.employees[userID]; !ok {
+		return "", my_errors.ErrUserNotFound
+	}
+	return r.store.passwords[userID], nil
+}
+
+func (r *userRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.employees[userID]; !ok {
+		return my_errors.ErrUserNotFound
+	}
+	r.store.passwords[userID] = hash
+	return nil
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.employees {
+		if existing.Username == user.Username {
+			return models.User{}, my_errors.ErrUsernameTaken
+		}
+	}
+
+	now := time.Now().UTC()
+	user.ID = uuid.NewString()
+	user.Active = true
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.store.employees[user.ID] = user
+	return user, nil
+}
+
+func (r *userRepository) ListUsers(ctx context.Context, filter repository.UserFilter) ([]models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	search := strings.ToLower(filter.Search)
+	var users []models.User
+	for _, user := range r.store.employees {
+		if !user.Active && !filter.IncludeInactive {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(user.Username), search) &&
+			!strings.Contains(strings.ToLower(user.FirstName), search) &&
+			!strings.Contains(strings.ToLower(user.LastName), search) {
+			continue
+		}
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	page := []models.User{}
+	for i := filter.Offset; i < len(users) && len(page) < filter.Limit; i++ {
+		page = append(page, users[i])
+	}
+	return page, nil
+}
+
+func (r *userRepository) UpdateUserNames(ctx context.Context, userID string, firstName, lastName *string) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.employees[userID]
+	if !ok || !user.Active {
+		return nil, my_errors.ErrUserNotFound
+	}
+	if firstName != nil {
+		user.FirstName = *firstName
+	}
+	if lastName != nil {
+		user.LastName = *lastName
+	}
+	user.UpdatedAt = time.Now().UTC()
+	r.store.employees[userID] = user
+	return &user, nil
+}
+
+func (r *userRepository) DeactivateUser(ctx context.Context, userID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.employees[userID]
+	if !ok || !user.Active {
+		return my_errors.ErrUserNotFound
+	}
+	user.Active = false
+	user.UpdatedAt = time.Now().UTC()
+	r.store.employees[userID] = user
+	return nil
+}
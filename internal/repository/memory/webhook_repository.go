@@ -0,0 +1,238 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type webhookRepository struct {
+	store *Store
+}
+
+func NewWebhookRepository(store *Store) repository.WebhookRepository {
+	return &webhookRepository{store: store}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	subscription.ID = uuid.NewString()
+	subscription.EventTypes = append([]string{}, subscription.EventTypes...)
+	subscription.Active = true
+	subscription.ConsecutiveFailures = 0
+	subscription.DisabledAt = nil
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+	r.store.webhooks[subscription.ID] = subscription
+	return subscription, nil
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, subscriptionID string) (models.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscription, ok := r.store.webhooks[subscriptionID]
+	if !ok {
+		return models.WebhookSubscription{}, my_errors.ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context, organizationID string) ([]models.WebhookSubscription, error) {
+	return r.filter(func(subscription models.WebhookSubscription) bool {
+		return subscription.OrganizationID == organizationID
+	}), nil
+}
+
+func (r *webhookRepository) MatchSubscriptions(ctx context.Context, organizationIDs []string, eventType string) ([]models.WebhookSubscription, error) {
+	return r.filter(func(subscription models.WebhookSubscription) bool {
+		return subscription.Active && containsString(organizationIDs, subscription.OrganizationID) && subscription.Wants(eventType)
+	}), nil
+}
+
+func (r *webhookRepository) filter(keep func(models.WebhookSubscription) bool) []models.WebhookSubscription {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscriptions := []models.WebhookSubscription{}
+	for _, subscription := range r.store.webhooks {
+		if keep(subscription) {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if !subscriptions[i].CreatedAt.Equal(subscriptions[j].CreatedAt) {
+			return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+		}
+		return subscriptions[i].ID < subscriptions[j].ID
+	})
+	return subscriptions
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.webhooks[subscription.ID]
+	if !ok {
+		return models.WebhookSubscription{}, my_errors.ErrWebhookNotFound
+	}
+	stored.URL = subscription.URL
+	stored.Secret = subscription.Secret
+	stored.EventTypes = append([]string{}, subscription.EventTypes...)
+	stored.Active = subscription.Active
+	stored.ConsecutiveFailures = subscription.ConsecutiveFailures
+	stored.DisabledAt = subscription.DisabledAt
+	stored.UpdatedAt = time.Now().UTC()
+	r.store.webhooks[stored.ID] = stored
+	return stored, nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhooks[subscriptionID]; !ok {
+		return my_errors.ErrWebhookNotFound
+	}
+	delete(r.store.webhooks, subscriptionID)
+
+	kept := r.store.webhookDeliveries[:0]
+	for _, delivery := range r.store.webhookDeliveries {
+		if delivery.SubscriptionID != subscriptionID {
+			kept = append(kept, delivery)
+		}
+	}
+	r.store.webhookDeliveries = kept
+	return nil
+}
+
+func (r *webhookRepository) EnqueueDeliveries(ctx context.Context, deliveries ...models.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now().UTC()
+	for _, delivery := range deliveries {
+		if r.hasDelivery(delivery.SubscriptionID, delivery.EventID) {
+			continue
+		}
+		delivery.ID = uuid.NewString()
+		delivery.Status = models.WebhookDeliveryPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = now
+		delivery.CreatedAt = now
+		r.store.webhookDeliveries = append(r.store.webhookDeliveries, delivery)
+	}
+	return nil
+}
+
+func (r *webhookRepository) hasDelivery(subscriptionID string, eventID int64) bool {
+	for _, delivery := range r.store.webhookDeliveries {
+		if delivery.SubscriptionID == subscriptionID && delivery.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	claimed := []models.WebhookDelivery{}
+	for i := range r.store.webhookDeliveries {
+		delivery := &r.store.webhookDeliveries[i]
+		if len(claimed) == limit {
+			break
+		}
+		if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) || !r.store.webhooks[delivery.SubscriptionID].Active {
+			continue
+		}
+		delivery.Attempts++
+		delivery.NextAttemptAt = now.Add(lease)
+		claimed = append(claimed, *delivery)
+	}
+	return claimed, nil
+}
+
+func (r *webhookRepository) MarkDeliverySucceeded(ctx context.Context, deliveryID string, responseStatus int, deliveredAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delivery := r.delivery(deliveryID)
+	if delivery == nil {
+		return nil
+	}
+	delivery.Status = models.WebhookDeliveryDelivered
+	delivery.ResponseStatus = responseStatus
+	delivery.LastError = ""
+	delivery.DeliveredAt = &deliveredAt
+
+	if subscription, ok := r.store.webhooks[delivery.SubscriptionID]; ok {
+		subscription.ConsecutiveFailures = 0
+		r.store.webhooks[subscription.ID] = subscription
+	}
+	return nil
+}
+
+func (r *webhookRepository) MarkDeliveryFailed(ctx context.Context, deliveryID string, responseStatus int, reason string, nextAttemptAt time.Time, final bool, disableAfter int) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delivery := r.delivery(deliveryID)
+	if delivery == nil {
+		return false, nil
+	}
+	if final {
+		delivery.Status = models.WebhookDeliveryFailed
+	}
+	delivery.ResponseStatus = responseStatus
+	delivery.LastError = reason
+	delivery.NextAttemptAt = nextAttemptAt
+
+	subscription, ok := r.store.webhooks[delivery.SubscriptionID]
+	if !ok {
+		return false, nil
+	}
+	subscription.ConsecutiveFailures++
+	if subscription.Active && subscription.ConsecutiveFailures >= disableAfter {
+		now := time.Now().UTC()
+		subscription.Active = false
+		subscription.DisabledAt = &now
+	}
+	r.store.webhooks[subscription.ID] = subscription
+	return !subscription.Active, nil
+}
+
+// delivery finds a stored delivery; the caller holds the lock.
+func (r *webhookRepository) delivery(deliveryID string) *models.WebhookDelivery {
+	for i := range r.store.webhookDeliveries {
+		if r.store.webhookDeliveries[i].ID == deliveryID {
+			return &r.store.webhookDeliveries[i]
+		}
+	}
+	return nil
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]models.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// Deliveries are stored oldest first and listed newest first.
+	deliveries := []models.WebhookDelivery{}
+	for i := len(r.store.webhookDeliveries) - 1; i >= 0; i-- {
+		if delivery := r.store.webhookDeliveries[i]; delivery.SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return paginate(deliveries, limit, offset), nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"tender-service/internal/metrics"
+)
+
+// QueryDuration records how long SQL statements take by repository and
+// statement kind. For queries it covers the time to the first row, not the
+// scanning of the rest. Register it with a metrics.Registry to expose it.
+var QueryDuration = metrics.NewHistogram("db_query_duration_seconds", "Duration of SQL statements by repository.", nil, "repository", "statement")
+
+// instrumentedQuerier times every statement run through it.
+type instrumentedQuerier struct {
+	db         querier
+	repository string
+}
+
+func instrument(db querier, repository string) querier {
+	return &instrumentedQuerier{db: db, repository: repository}
+}
+
+func (q *instrumentedQuerier) observe(query string, start time.Time) {
+	QueryDuration.Observe(time.Since(start).Seconds(), q.repository, statementKind(query))
+}
+
+func (q *instrumentedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer q.observe(query, time.Now())
+	return q.db.ExecContext(ctx, query, args...)
+}
+
+func (q *instrumentedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	defer q.observe(query, time.Now())
+	return q.db.QueryContext(ctx, query, args...)
+}
+
+func (q *instrumentedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	defer q.observe(query, time.Now())
+	return q.db.QueryRowContext(ctx, query, args...)
+}
+
+// statementKind is the lower-cased leading keyword of query, such as select
+// or insert, or other for anything unexpected, which keeps the label bounded.
+func statementKind(query string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	keyword, _, _ = strings.Cut(keyword, "\n")
+	switch keyword = strings.ToLower(keyword); keyword {
+	case "select", "insert", "update", "delete", "with":
+		return keyword
+	default:
+		return "other"
+	}
+}
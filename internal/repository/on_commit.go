@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"tender-service/internal/event"
+)
+
+// OnCommit returns a UnitOfWork that calls observe with the events a unit
+// appended to the outbox, once and only once that unit has committed. Unlike
+// outbox delivery, which may repeat an event, it suits counting them.
+func OnCommit(uow UnitOfWork, observe func(ctx context.Context, events []event.Event)) UnitOfWork {
+	return &observedUnitOfWork{uow: uow, observe: observe}
+}
+
+type observedUnitOfWork struct {
+	uow     UnitOfWork
+	observe func(ctx context.Context, events []event.Event)
+}
+
+func (u *observedUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	var appended []event.Event
+	err := u.uow.Do(ctx, func(ctx context.Context, repos Repositories) error {
+		repos.Outbox = &recordingOutbox{OutboxRepository: repos.Outbox, appended: &appended}
+		return fn(ctx, repos)
+	})
+	if err == nil && len(appended) > 0 {
+		u.observe(ctx, appended)
+	}
+	return err
+}
+
+// recordingOutbox remembers the events appended through it.
+type recordingOutbox struct {
+	OutboxRepository
+	appended *[]event.Event
+}
+
+func (o *recordingOutbox) Append(ctx context.Context, events ...event.Event) error {
+	if err := o.OutboxRepository.Append(ctx, events...); err != nil {
+		return err
+	}
+	*o.appended = append(*o.appended, events...)
+	return nil
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+	"time"
+)
+
+type OrganizationRepository interface {
+	CreateOrganization(ctx context.Context, organization models.Organization, responsibleID string) (models.Organization, error)
+	GetOrganizations(ctx context.Context) ([]models.Organization, error)
+	GetOrganizationByID(ctx context.Context, organizationID string) (models.Organization, error)
+	GetUserOrganizations(ctx context.Context, userID string) ([]models.Organization, error)
+	GetResponsibles(ctx context.Context, organizationID string) ([]models.User, error)
+	IsResponsible(ctx context.Context, userID, organizationID string) (bool, error)
+	AddResponsible(ctx context.Context, organizationID, userID string) error
+	RemoveResponsible(ctx context.Context, organizationID, userID string) error
+}
+
+type organizationRepository struct {
+	// db begins the transactions of multi-statement writes; single statements
+	// go through queries.
+	db           *sql.DB
+	queries      querier
+	queryTimeout time.Duration
+}
+
+func NewOrganizationRepository(db *sql.DB, queryTimeout time.Duration) OrganizationRepository {
+	return &organizationRepository{db: db, queries: instrument(db, "organization"), queryTimeout: queryTimeout}
+}
+
+const organizationColumns = "id, name, COALESCE(description, ''), type, created_at, updated_at"
+
+func scanOrganization(row interface{ Scan(...interface{}) error }, organization *models.Organization) error {
+	return row.Scan(&organization.ID, &organization.Name, &organization.Description, &organization.Type, &organization.CreatedAt, &organization.UpdatedAt)
+}
+
+// CreateOrganization stores the organization together with its first
+// responsible, so that a new organization always has someone to manage it.
+func (r *organizationRepository) CreateOrganization(ctx context.Context, organization models.Organization, responsibleID string) (models.Organization, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Organization{}, err
+	}
+	defer tx.Rollback()
+	queries := instrument(tx, "organization")
+
+	query := `
+		INSERT INTO organization (name, description, type)
+		VALUES ($1, $2, $3)
+		RETURNING ` + organizationColumns
+	var created models.Organization
+	err = scanOrganization(queries.QueryRowContext(ctx, query, organization.Name, organization.Description, organization.Type), &created)
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	query = "INSERT INTO organization_responsible (organization_id, user_id) VALUES ($1, $2)"
+	if _, err := queries.ExecContext(ctx, query, created.ID, responsibleID); err != nil {
+		return models.Organization{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Organization{}, err
+	}
+	return created, nil
+}
+
+func (r *organizationRepository) GetOrganizations(ctx context.Context) ([]models.Organization, error) {
+	query := "SELECT " + organizationColumns + " FROM organization ORDER BY name"
+	return r.queryOrganizations(ctx, query)
+}
+
+func (r *organizationRepository) GetOrganizationByID(ctx context.Context, organizationID string) (models.Organization, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := "SELECT " + organizationColumns + " FROM organization WHERE id = $1"
+	var organization models.Organization
+	err := scanOrganization(r.queries.QueryRowContext(ctx, query, organizationID), &organization)
+	if err == sql.ErrNoRows {
+		return models.Organization{}, my_errors.ErrOrganizationNotFound
+	} else if err != nil {
+		return models.Organization{}, err
+	}
+	return organization, nil
+}
+
+func (r *organizationRepository) GetUserOrganizations(ctx context.Context, userID string) ([]models.Organization, error) {
+	query := `
+		SELECT o.id, o.name, COALESCE(o.description, ''), o.type, o.created_at, o.updated_at
+		FROM organization o
+		JOIN organization_responsible r ON r.organization_id = o.id
+		WHERE r.user_id = $1
+		ORDER BY o.name
+	`
+	return r.queryOrganizations(ctx, query, userID)
+}
+
+func (r *organizationRepository) queryOrganizations(ctx context.Context, query string, args ...interface{}) ([]models.Organization, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.queries.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []models.Organization{}
+	for rows.Next() {
+		var organization models.Organization
+		if err := scanOrganization(rows, &organization); err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, organization)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+func (r *organizationRepository) GetResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT e.id, e.username, COALESCE(e.first_name, ''), COALESCE(e.last_name, ''), e.created_at, e.updated_at
+		FROM employee e
+		JOIN organization_responsible r ON r.user_id = e.id
+		WHERE r.organization_id = $1
+		ORDER BY e.username
+	`
+	rows, err := r.queries.QueryContext(ctx, query, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *organizationRepository) IsResponsible(ctx context.Context, userID, organizationID string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var exists bool
+	query := "SELECT EXISTS(SELECT 1 FROM organization_responsible WHERE user_id = $1 AND organization_id = $2)"
+	err := r.queries.QueryRowContext(ctx, query, userID, organizationID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *organizationRepository) AddResponsible(ctx context.Context, organizationID, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO organization_responsible (organization_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (organization_id, user_id) DO NOTHING
+	`
+	result, err := r.queries.ExecContext(ctx, query, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return my_errors.ErrResponsibleExists
+	}
+	return nil
+}
+
+// RemoveResponsible locks the organization row while counting responsibles,
+// so that two concurrent removals cannot leave it without any.
+func (r *organizationRepository) RemoveResponsible(ctx context.Context, organizationID, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	queries := instrument(tx, "organization")
+
+	var id string
+	err = queries.QueryRowContext(ctx, "SELECT id FROM organization WHERE id = $1 FOR UPDATE", organizationID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return my_errors.ErrOrganizationNotFound
+	} else if err != nil {
+		return err
+	}
+
+	var count int
+	err = queries.QueryRowContext(ctx, "SELECT COUNT(1) FROM organization_responsible WHERE organization_id = $1", organizationID).Scan(&count)
+	if err != nil {
+		return err
+	}
+
+	result, err := queries.ExecContext(ctx, "DELETE FROM organization_responsible WHERE organization_id = $1 AND user_id = $2", organizationID, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return my_errors.ErrResponsibleNotFound
+	}
+	if count <= 1 {
+		return my_errors.ErrLastResponsible
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type OutboxRepository interface {
+	// Append stores events for delivery. Within a UnitOfWork it commits or
+	// rolls back together with the change the events describe.
+	Append(ctx context.Context, events ...event.Event) error
+	// ClaimDue takes up to limit pending entries due at now, counts an
+	// attempt for each and hides them from other claimers for lease. An
+	// entry that is neither marked sent nor failed within the lease, because
+	// its claimer crashed, is claimed again.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEntry, error)
+	MarkSent(ctx context.Context, id int64, sentAt time.Time) error
+	// MarkFailed records why an attempt failed. The entry is retried at
+	// nextAttemptAt unless final, in which case it is failed for good.
+	MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, final bool) error
+	ListOutbox(ctx context.Context, statuses []models.OutboxStatus, limit, offset int) ([]models.OutboxEntry, error)
+}
+
+type outboxRepository struct {
+	db           querier
+	queryTimeout time.Duration
+}
+
+func NewOutboxRepository(db *sql.DB, queryTimeout time.Duration) OutboxRepository {
+	return &outboxRepository{db: instrument(db, "outbox"), queryTimeout: queryTimeout}
+}
+
+const outboxColumns = "id, event_type, subject_id, payload, status, attempts, COALESCE(last_error, ''), next_attempt_at, created_at, sent_at"
+
+func scanOutboxEntry(row interface{ Scan(...interface{}) error }, entry *models.OutboxEntry) error {
+	var sentAt sql.NullTime
+	var payload []byte
+	if err := row.Scan(&entry.ID, &entry.EventType, &entry.SubjectID, &payload, &entry.Status, &entry.Attempts, &entry.LastError, &entry.NextAttemptAt, &entry.CreatedAt, &sentAt); err != nil {
+		return err
+	}
+	entry.Payload = payload
+	entry.SentAt = nullTimePtr(sentAt)
+	return nil
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events ...event.Event) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		query := "INSERT INTO outbox (event_type, subject_id, payload) VALUES ($1, $2, $3)"
+		if _, err := r.db.ExecContext(ctx, query, string(e.Type()), e.SubjectID(), payload); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEntry, error) {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE status = 'PENDING' AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + outboxColumns
+	return r.list(ctx, query, now, now.Add(lease), limit)
+}
+
+func (r *outboxRepository) MarkSent(ctx context.Context, id int64, sentAt time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, "UPDATE outbox SET status = 'SENT', sent_at = $2, last_error = NULL WHERE id = $1", id, sentAt)
+	return err
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, reason string, nextAttemptAt time.Time, final bool) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	status := models.OutboxPending
+	if final {
+		status = models.OutboxFailed
+	}
+	query := "UPDATE outbox SET status = $2, last_error = $3, next_attempt_at = $4 WHERE id = $1"
+	_, err := r.db.ExecContext(ctx, query, id, status, reason, nextAttemptAt)
+	return err
+}
+
+// ListOutbox returns entries with one of statuses, or with any status when
+// statuses is empty, oldest first.
+func (r *outboxRepository) ListOutbox(ctx context.Context, statuses []models.OutboxStatus, limit, offset int) ([]models.OutboxEntry, error) {
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+	query := "SELECT " + outboxColumns + " FROM outbox WHERE cardinality($1::text[]) = 0 OR status = ANY($1) ORDER BY id LIMIT $2 OFFSET $3"
+	return r.list(ctx, query, pq.Array(values), limit, offset)
+}
+
+func (r *outboxRepository) list(ctx context.Context, query string, args ...interface{}) ([]models.OutboxEntry, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.OutboxEntry{}
+	for rows.Next() {
+		var entry models.OutboxEntry
+		if err := scanOutboxEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"tender-service/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// SortField is a field list queries may be ordered by.
+type SortField string
+
+const (
+	SortByName      SortField = "name"
+	SortByCreatedAt SortField = "createdAt"
+	SortByUpdatedAt SortField = "updatedAt"
+)
+
+func ParseSortField(field string) (SortField, error) {
+	switch field {
+	case "", string(SortByName):
+		return SortByName, nil
+	case string(SortByCreatedAt):
+		return SortByCreatedAt, nil
+	case string(SortByUpdatedAt):
+		return SortByUpdatedAt, nil
+	default:
+		return "", fmt.Errorf("invalid sort field %q", field)
+	}
+}
+
+// Cursor is a keyset position in a list sorted by creation time: the
+// creation time and ID of the last item of the previous page.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(token string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+	// IDs are compared as uuids, so anything else would fail in the query.
+	if _, err := uuid.Parse(id); err != nil {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+	return Cursor{CreatedAt: t, ID: id}, nil
+}
+
+func TenderCursor(tender models.Tender) Cursor {
+	return Cursor{CreatedAt: tender.CreatedAt, ID: tender.ID}
+}
+
+// BidCursor reads the bid's creation time, which bids keep as an RFC 3339
+// string.
+func BidCursor(bid models.Bid) Cursor {
+	createdAt, _ := time.Parse(time.RFC3339Nano, bid.CreatedAt)
+	return Cursor{CreatedAt: createdAt, ID: bid.ID}
+}
+
+// QueryOptions narrows, orders and pages list queries. Empty filters match
+// everything, zero times leave the creation range open and a non-positive
+// Limit returns all rows. Lists without a given field ignore its filter: bids
+// have no service type and are sorted by description in place of a name.
+//
+// After switches to keyset paging: only items past the cursor in
+// (created_at, id) order are returned. It requires SortBy to be
+// SortByCreatedAt and is not combined with Offset.
+type QueryOptions struct {
+	Limit  int
+	Offset int
+	After  *Cursor
+
+	Statuses        []string
+	ServiceTypes    []string
+	OrganizationIDs []string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+
+	SortBy     SortField
+	Descending bool
+}
+
+// listColumns maps QueryOptions fields to the columns of one table. An empty
+// column means the table has no such field.
+type listColumns struct {
+	ID             string
+	Name           string
+	Status         string
+	ServiceType    string
+	OrganizationID string
+	CreatedAt      string
+	UpdatedAt      string
+}
+
+var (
+	tenderListColumns = listColumns{
+		ID:             "id",
+		Name:           "name",
+		Status:         "status",
+		ServiceType:    "service_type",
+		OrganizationID: "organization_id",
+		CreatedAt:      "created_at",
+		UpdatedAt:      "updated_at",
+	}
+	bidListColumns = listColumns{
+		ID:             "id",
+		Name:           "description",
+		Status:         "status",
+		OrganizationID: "organization_id",
+		CreatedAt:      "created_at",
+		UpdatedAt:      "updated_at",
+	}
+)
+
+// filterSQL returns the option filters as " AND ..." conditions, appending
+// their arguments to args.
+func (o QueryOptions) filterSQL(columns listColumns, args []interface{}) (string, []interface{}) {
+	var conditions strings.Builder
+	in := func(column string, values []string) {
+		if column == "" || len(values) == 0 {
+			return
+		}
+		args = append(args, pq.Array(values))
+		fmt.Fprintf(&conditions, " AND %s::text = ANY($%d)", column, len(args))
+	}
+	in(columns.Status, o.Statuses)
+	in(columns.ServiceType, o.ServiceTypes)
+	in(columns.OrganizationID, o.OrganizationIDs)
+
+	if !o.CreatedFrom.IsZero() {
+		args = append(args, o.CreatedFrom)
+		fmt.Fprintf(&conditions, " AND %s >= $%d", columns.CreatedAt, len(args))
+	}
+	if !o.CreatedTo.IsZero() {
+		args = append(args, o.CreatedTo)
+		fmt.Fprintf(&conditions, " AND %s < $%d", columns.CreatedAt, len(args))
+	}
+	if o.After != nil {
+		comparison := ">"
+		if o.Descending {
+			comparison = "<"
+		}
+		args = append(args, o.After.CreatedAt, o.After.ID)
+		fmt.Fprintf(&conditions, " AND (%s, %s) %s ($%d, $%d)", columns.CreatedAt, columns.ID, comparison, len(args)-1, len(args))
+	}
+	return conditions.String(), args
+}
+
+// pageSQL returns the ORDER BY, LIMIT and OFFSET clauses, appending their
+// arguments to args. Rows with equal sort keys are ordered by ID so that
+// pages do not overlap.
+func (o QueryOptions) pageSQL(columns listColumns, args []interface{}) (string, []interface{}) {
+	column := columns.Name
+	switch o.SortBy {
+	case SortByCreatedAt:
+		column = columns.CreatedAt
+	case SortByUpdatedAt:
+		column = columns.UpdatedAt
+	}
+	direction := "ASC"
+	if o.Descending {
+		direction = "DESC"
+	}
+
+	clauses := fmt.Sprintf(" ORDER BY %s %s, %s %s", column, direction, columns.ID, direction)
+	limit, args := o.limitSQL(args)
+	return clauses + limit, args
+}
+
+// limitSQL returns the LIMIT and OFFSET clauses, appending their arguments to
+// args.
+func (o QueryOptions) limitSQL(args []interface{}) (string, []interface{}) {
+	var clauses string
+	if o.Limit > 0 {
+		args = append(args, o.Limit)
+		clauses += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if o.Offset > 0 {
+		args = append(args, o.Offset)
+		clauses += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+	return clauses, args
+}
+
+// Searches mark hits with private-use characters instead of tags, so that
+// the text around them can be escaped. The characters are stripped from the
+// searched text first, so they cannot be forged.
+const (
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+)
+
+// searchHeadline is the ts_headline configuration shared by searches.
+const searchHeadline = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxFragments=2, MaxWords=20, MinWords=5"
+
+// headlineSQL is the ts_headline call highlighting document, an SQL
+// expression, for the query q with the options in parameter $param.
+func headlineSQL(document string, param int) string {
+	return fmt.Sprintf("ts_headline('simple', translate(%s, '%s', ''), q, $%d)", document, highlightStart+highlightStop, param)
+}
+
+var highlightTags = strings.NewReplacer(highlightStart, "<b>", highlightStop, "</b>")
+
+// escapeHighlight HTML-escapes a headline and turns its hit markers into
+// <b></b>, so that names and descriptions cannot inject markup.
+func escapeHighlight(headline string) string {
+	return highlightTags.Replace(html.EscapeString(headline))
+}
@@ -1,44 +1,93 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	my_errors "tender-service/internal/errors"
 	"tender-service/internal/models"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type TenderRepository interface {
-	GetTenders(serviceType string) ([]models.Tender, error)
-	CreateTender(tender models.Tender) (models.Tender, error)
-	GetTenderByID(tenderId string) (models.Tender, error)
-	UpdateTenderStatus(tender models.Tender) error
-	UpdateTender(tender models.Tender) error
-	GetUserTenders(username string) ([]models.Tender, error)
-	IsUserResponsibleForOrganization(userId, organizationId string) (bool, error)
-	GetTenderHistoryVersion(tenderId string, version int) (models.TenderHistory, error)
-	GetTenderHistoryByVersion(tenderId string, version int) (models.TenderHistory, error)
+	GetTenders(ctx context.Context, filter TenderFilter, options QueryOptions) ([]models.Tender, error)
+	SearchTenders(ctx context.Context, query string, filter TenderFilter, options QueryOptions) ([]models.TenderSearchResult, error)
+	CreateTender(ctx context.Context, tender models.Tender) (models.Tender, error)
+	GetTenderByID(ctx context.Context, tenderId string) (models.Tender, error)
+	// GetTenderForShare is GetTenderByID that, within a UnitOfWork, also
+	// keeps the tender from changing until the unit ends.
+	GetTenderForShare(ctx context.Context, tenderId string) (models.Tender, error)
+	// GetTenderForUpdate is GetTenderByID that, within a UnitOfWork, also
+	// keeps other units from changing or locking the tender until it ends.
+	GetTenderForUpdate(ctx context.Context, tenderId string) (models.Tender, error)
+	UpdateTenderStatus(ctx context.Context, tender models.Tender) (models.Tender, error)
+	UpdateTender(ctx context.Context, tender models.Tender) (models.Tender, error)
+	IsUserResponsibleForOrganization(ctx context.Context, userId, organizationId string) (bool, error)
+	GetUserOrganizationIDs(ctx context.Context, userId string) ([]string, error)
+	GetTenderHistoryVersion(ctx context.Context, tenderId string, version int) (models.TenderHistory, error)
+	GetTenderHistoryByVersion(ctx context.Context, tenderId string, version int) (models.TenderHistory, error)
+	CloseExpiredTenders(ctx context.Context, now time.Time, limit int) ([]models.Tender, error)
+}
+
+// TenderFilter selects the tenders a caller may see: those that either have
+// one of PublicStatuses or belong to one of OrganizationIDs.
+type TenderFilter struct {
+	PublicStatuses  []models.TenderStatus
+	OrganizationIDs []string
+}
+
+const tenderColumns = "id, name, description, service_type, status, organization_id, creator_id, version, created_at, updated_at, submission_deadline, decision_deadline"
+
+// scanTender reads a row selected with tenderColumns, followed by extra
+// columns, if any.
+func scanTender(row interface{ Scan(...interface{}) error }, tender *models.Tender, extra ...interface{}) error {
+	var submissionDeadline, decisionDeadline sql.NullTime
+	dest := []interface{}{&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status, &tender.OrganizationID, &tender.CreatorID, &tender.Version, &tender.CreatedAt, &tender.UpdatedAt, &submissionDeadline, &decisionDeadline}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	tender.SubmissionDeadline = nullTimePtr(submissionDeadline)
+	tender.DecisionDeadline = nullTimePtr(decisionDeadline)
+	return nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
 }
 
 type tenderRepository struct {
-	db *sql.DB
+	db           querier
+	queryTimeout time.Duration
 }
 
-func NewTenderRepository(db *sql.DB) TenderRepository {
-	return &tenderRepository{db: db}
+func NewTenderRepository(db *sql.DB, queryTimeout time.Duration) TenderRepository {
+	return &tenderRepository{db: instrument(db, "tender"), queryTimeout: queryTimeout}
 }
 
-func (r *tenderRepository) GetTenders(serviceType string) ([]models.Tender, error) {
+func (r *tenderRepository) GetTenders(ctx context.Context, filter TenderFilter, options QueryOptions) ([]models.Tender, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var tenders []models.Tender
-	var rows *sql.Rows
-	var err error
 
-	if serviceType != "" {
-		query := "SELECT id, name, description, service_type, status, organization_id, creator_id, version, created_at, updated_at FROM tender WHERE service_type = $1"
-		rows, err = r.db.Query(query, serviceType)
-	} else {
-		query := "SELECT id, name, description, service_type, status, organization_id, creator_id, version, created_at, updated_at FROM tender"
-		rows, err = r.db.Query(query)
+	publicStatuses := make([]string, len(filter.PublicStatuses))
+	for i, status := range filter.PublicStatuses {
+		publicStatuses[i] = string(status)
 	}
 
+	query := `
+		SELECT ` + tenderColumns + `
+		FROM tender
+		WHERE (status::text = ANY($1) OR organization_id::text = ANY($2))`
+	args := []interface{}{pq.Array(publicStatuses), pq.Array(filter.OrganizationIDs)}
+	conditions, args := options.filterSQL(tenderListColumns, args)
+	page, args := options.pageSQL(tenderListColumns, args)
+
+	rows, err := r.db.QueryContext(ctx, query+conditions+page, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +95,7 @@ func (r *tenderRepository) GetTenders(serviceType string) ([]models.Tender, erro
 
 	for rows.Next() {
 		var tender models.Tender
-		if err := rows.Scan(&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status, &tender.OrganizationID, &tender.CreatorID, &tender.Version, &tender.CreatedAt, &tender.UpdatedAt); err != nil {
+		if err := scanTender(rows, &tender); err != nil {
 			return nil, err
 		}
 		tenders = append(tenders, tender)
@@ -59,62 +108,82 @@ func (r *tenderRepository) GetTenders(serviceType string) ([]models.Tender, erro
 	return tenders, nil
 }
 
-func (r *tenderRepository) CreateTender(tender models.Tender) (models.Tender, error) {
-	queryInsertTender := `
-		INSERT INTO tender (name, description, service_type, status, organization_id, creator_id)
-		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, name, description, service_type, status, organization_id, creator_id, version, created_at, updated_at
-	`
-	err := r.db.QueryRow(queryInsertTender, tender.Name, tender.Description, tender.ServiceType, tender.Status, tender.OrganizationID, tender.CreatorID).
-		Scan(&tender.ID, &tender.Name, &tender.Description, &tender.ServiceType, &tender.Status, &tender.OrganizationID, &tender.CreatorID, &tender.Version, &tender.CreatedAt, &tender.UpdatedAt)
+// SearchTenders returns the tenders matching the web-search style query, best
+// matches first. options filter and page the results but do not sort them.
+func (r *tenderRepository) SearchTenders(ctx context.Context, query string, filter TenderFilter, options QueryOptions) ([]models.TenderSearchResult, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
 
-	return tender, err
-}
+	publicStatuses := make([]string, len(filter.PublicStatuses))
+	for i, status := range filter.PublicStatuses {
+		publicStatuses[i] = string(status)
+	}
 
-func (r *tenderRepository) GetUserTenders(username string) ([]models.Tender, error) {
-	var tenders []models.Tender
+	sqlQuery := `
+		SELECT ` + tenderColumns + `,
+		       ts_rank(search_vector, q) AS rank,
+		       ` + headlineSQL("name || ' ' || coalesce(description, '')", 4) + ` AS highlight
+		FROM tender, websearch_to_tsquery('simple', $1) q
+		WHERE search_vector @@ q
+		  AND (status::text = ANY($2) OR organization_id::text = ANY($3))`
+	args := []interface{}{query, pq.Array(publicStatuses), pq.Array(filter.OrganizationIDs), searchHeadline}
+	conditions, args := options.filterSQL(tenderListColumns, args)
+	limit, args := options.limitSQL(args)
 
-	query := `
-		SELECT t.id, t.name, t.description, t.service_type, t.status, t.organization_id, t.creator_id, t.version, t.created_at, t.updated_at
-		FROM tender t
-		JOIN employee e ON t.creator_id = e.id
-		WHERE e.username = $1
-	`
-	rows, err := r.db.Query(query, username)
+	rows, err := r.db.QueryContext(ctx, sqlQuery+conditions+" ORDER BY rank DESC, id"+limit, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	results := []models.TenderSearchResult{}
 	for rows.Next() {
-		var tender models.Tender
-		if err := rows.Scan(
-			&tender.ID,
-			&tender.Name,
-			&tender.Description,
-			&tender.ServiceType,
-			&tender.Status,
-			&tender.OrganizationID,
-			&tender.CreatorID,
-			&tender.Version,
-			&tender.CreatedAt,
-			&tender.UpdatedAt,
-		); err != nil {
+		var result models.TenderSearchResult
+		tender := &result.Tender
+		if err := scanTender(rows, tender, &result.Rank, &result.Highlight); err != nil {
 			return nil, err
 		}
-		tenders = append(tenders, tender)
+		result.Highlight = escapeHighlight(result.Highlight)
+		results = append(results, result)
 	}
-
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return results, nil
+}
 
-	return tenders, nil
+func (r *tenderRepository) CreateTender(ctx context.Context, tender models.Tender) (models.Tender, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	queryInsertTender := `
+		INSERT INTO tender (name, description, service_type, status, organization_id, creator_id, submission_deadline, decision_deadline)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING ` + tenderColumns
+	row := r.db.QueryRowContext(ctx, queryInsertTender, tender.Name, tender.Description, tender.ServiceType, tender.Status, tender.OrganizationID, tender.CreatorID, tender.SubmissionDeadline, tender.DecisionDeadline)
+
+	var created models.Tender
+	err := scanTender(row, &created)
+	return created, err
+}
+
+func (r *tenderRepository) GetTenderByID(ctx context.Context, tenderId string) (models.Tender, error) {
+	return r.getTender(ctx, "SELECT "+tenderColumns+" FROM tender WHERE id = $1", tenderId)
 }
 
-func (r *tenderRepository) GetTenderByID(tenderId string) (models.Tender, error) {
+func (r *tenderRepository) GetTenderForShare(ctx context.Context, tenderId string) (models.Tender, error) {
+	return r.getTender(ctx, "SELECT "+tenderColumns+" FROM tender WHERE id = $1 FOR SHARE", tenderId)
+}
+
+func (r *tenderRepository) GetTenderForUpdate(ctx context.Context, tenderId string) (models.Tender, error) {
+	return r.getTender(ctx, "SELECT "+tenderColumns+" FROM tender WHERE id = $1 FOR UPDATE", tenderId)
+}
+
+func (r *tenderRepository) getTender(ctx context.Context, query string, tenderId string) (models.Tender, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var tender models.Tender
-	query := "SELECT id, name, status, organization_id, creator_id FROM tender WHERE id = $1"
-	err := r.db.QueryRow(query, tenderId).Scan(&tender.ID, &tender.Name, &tender.Status, &tender.OrganizationID, &tender.CreatorID)
+	err := scanTender(r.db.QueryRowContext(ctx, query, tenderId), &tender)
 	if err == sql.ErrNoRows {
 		return tender, my_errors.ErrTenderNotFound
 	} else if err != nil {
@@ -122,38 +191,100 @@ func (r *tenderRepository) GetTenderByID(tenderId string) (models.Tender, error)
 	}
 	return tender, nil
 }
-func (r *tenderRepository) UpdateTenderStatus(tender models.Tender) error {
-	query := `UPDATE tender SET status = $1, updated_at = NOW() WHERE id = $2`
-	_, err := r.db.Exec(query, tender.Status, tender.ID)
-	return err
+
+// UpdateTenderStatus and UpdateTender only succeed while the stored version
+// still equals tender.Version, so concurrent read-modify-write cycles cannot
+// silently overwrite each other.
+func (r *tenderRepository) UpdateTenderStatus(ctx context.Context, tender models.Tender) (models.Tender, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+        UPDATE tender
+        SET status = $1, updated_at = NOW()
+        WHERE id = $2 AND version = $3
+        RETURNING ` + tenderColumns
+	row := r.db.QueryRowContext(ctx, query, tender.Status, tender.ID, tender.Version)
+	return r.scanUpdated(ctx, row, tender.ID)
 }
 
-func (r *tenderRepository) UpdateTender(tender models.Tender) error {
+func (r *tenderRepository) UpdateTender(ctx context.Context, tender models.Tender) (models.Tender, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
         UPDATE tender
-        SET name = $1, description = $2, service_type = $3, updated_at = NOW()
-        WHERE id = $4
-    `
-	_, err := r.db.Exec(query, tender.Name, tender.Description, tender.ServiceType, tender.ID)
-	return err
+        SET name = $1, description = $2, service_type = $3, submission_deadline = $4, decision_deadline = $5, updated_at = NOW()
+        WHERE id = $6 AND version = $7
+        RETURNING ` + tenderColumns
+	row := r.db.QueryRowContext(ctx, query, tender.Name, tender.Description, tender.ServiceType, tender.SubmissionDeadline, tender.DecisionDeadline, tender.ID, tender.Version)
+	return r.scanUpdated(ctx, row, tender.ID)
 }
 
-func (r *tenderRepository) IsUserResponsibleForOrganization(userId, organizationId string) (bool, error) {
+func (r *tenderRepository) scanUpdated(ctx context.Context, row *sql.Row, tenderId string) (models.Tender, error) {
+	var tender models.Tender
+	err := scanTender(row, &tender)
+	if err == sql.ErrNoRows {
+		if _, err := r.GetTenderByID(ctx, tenderId); err != nil {
+			return tender, err
+		}
+		return tender, my_errors.ErrVersionConflict
+	} else if err != nil {
+		return tender, err
+	}
+	return tender, nil
+}
+
+func (r *tenderRepository) IsUserResponsibleForOrganization(ctx context.Context, userId, organizationId string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var exists bool
 	query := "SELECT EXISTS(SELECT 1 FROM organization_responsible WHERE user_id = $1 AND organization_id = $2)"
-	err := r.db.QueryRow(query, userId, organizationId).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, userId, organizationId).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
 	return exists, nil
 }
 
-func (r *tenderRepository) GetTenderHistoryVersion(tenderId string, version int) (models.TenderHistory, error) {
+func (r *tenderRepository) GetUserOrganizationIDs(ctx context.Context, userId string) ([]string, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := "SELECT organization_id FROM organization_responsible WHERE user_id = $1"
+	rows, err := r.db.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var organizationIDs []string
+	for rows.Next() {
+		var organizationID string
+		if err := rows.Scan(&organizationID); err != nil {
+			return nil, err
+		}
+		organizationIDs = append(organizationIDs, organizationID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return organizationIDs, nil
+}
+
+func (r *tenderRepository) GetTenderHistoryVersion(ctx context.Context, tenderId string, version int) (models.TenderHistory, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var history models.TenderHistory
-	query := `SELECT tender_id, name, description, service_type, status, organization_id, creator_id, version, updated_at
+	var submissionDeadline, decisionDeadline sql.NullTime
+	query := `SELECT tender_id, name, description, service_type, status, organization_id, creator_id, version, updated_at, submission_deadline, decision_deadline
               FROM tender_history
               WHERE tender_id = $1 AND version = $2`
-	err := r.db.QueryRow(query, tenderId, version).Scan(
+	err := r.db.QueryRowContext(ctx, query, tenderId, version).Scan(
 		&history.TenderID,
 		&history.Name,
 		&history.Description,
@@ -163,7 +294,11 @@ func (r *tenderRepository) GetTenderHistoryVersion(tenderId string, version int)
 		&history.CreatorID,
 		&history.Version,
 		&history.UpdatedAt,
+		&submissionDeadline,
+		&decisionDeadline,
 	)
+	history.SubmissionDeadline = nullTimePtr(submissionDeadline)
+	history.DecisionDeadline = nullTimePtr(decisionDeadline)
 	if err == sql.ErrNoRows {
 		return history, my_errors.ErrTenderHistoryNotFound
 	} else if err != nil {
@@ -172,14 +307,18 @@ func (r *tenderRepository) GetTenderHistoryVersion(tenderId string, version int)
 	return history, nil
 }
 
-func (r *tenderRepository) GetTenderHistoryByVersion(tenderId string, version int) (models.TenderHistory, error) {
+func (r *tenderRepository) GetTenderHistoryByVersion(ctx context.Context, tenderId string, version int) (models.TenderHistory, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	query := `
-        SELECT id, tender_id, name, description, service_type, status, organization_id, creator_id, version, updated_at
+        SELECT id, tender_id, name, description, service_type, status, organization_id, creator_id, version, updated_at, submission_deadline, decision_deadline
         FROM tender_history
         WHERE tender_id = $1 AND version = $2
     `
 	var history models.TenderHistory
-	err := r.db.QueryRow(query, tenderId, version).Scan(
+	var submissionDeadline, decisionDeadline sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, tenderId, version).Scan(
 		&history.ID,
 		&history.TenderID,
 		&history.Name,
@@ -190,7 +329,11 @@ func (r *tenderRepository) GetTenderHistoryByVersion(tenderId string, version in
 		&history.CreatorID,
 		&history.Version,
 		&history.UpdatedAt,
+		&submissionDeadline,
+		&decisionDeadline,
 	)
+	history.SubmissionDeadline = nullTimePtr(submissionDeadline)
+	history.DecisionDeadline = nullTimePtr(decisionDeadline)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return history, my_errors.ErrTenderHistoryNotFound
@@ -199,3 +342,43 @@ func (r *tenderRepository) GetTenderHistoryByVersion(tenderId string, version in
 	}
 	return history, nil
 }
+
+// CloseExpiredTenders closes up to limit published tenders whose expiry time
+// (see models.Tender.ExpiresAt) is not after now and returns them. Rows locked
+// by another transaction are skipped rather than waited for, so several
+// replicas may run it at once without closing a tender twice.
+func (r *tenderRepository) CloseExpiredTenders(ctx context.Context, now time.Time, limit int) ([]models.Tender, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE tender
+		SET status = 'CLOSED', updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM tender
+			WHERE status = 'PUBLISHED' AND COALESCE(decision_deadline, submission_deadline) <= $1
+			ORDER BY COALESCE(decision_deadline, submission_deadline)
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + tenderColumns
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tenders []models.Tender
+	for rows.Next() {
+		var tender models.Tender
+		if err := scanTender(rows, &tender); err != nil {
+			return nil, err
+		}
+		tenders = append(tenders, tender)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tenders, nil
+}
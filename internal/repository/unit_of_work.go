@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// querier is the part of *sql.DB and *sql.Tx that repositories use, so the
+// same repository code runs inside and outside a transaction.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withQueryTimeout bounds a repository call by timeout on top of any deadline
+// ctx already has. A non-positive timeout leaves ctx unbounded.
+func withQueryTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// Repositories are bound to one transaction by a UnitOfWork.
+type Repositories struct {
+	Tenders TenderRepository
+	Bids    BidRepository
+	Users   UserRepository
+	Outbox  OutboxRepository
+}
+
+// UnitOfWork runs multi-step operations atomically. Do calls fn with
+// repositories sharing one transaction, which commits when fn returns nil and
+// rolls back when it returns an error or panics. The transaction is bound to
+// ctx: cancelling ctx rolls it back.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db           *sql.DB
+	queryTimeout time.Duration
+}
+
+// NewUnitOfWork returns a UnitOfWork whose repositories bound each call by
+// queryTimeout, like the ones built by NewTenderRepository and friends.
+func NewUnitOfWork(db *sql.DB, queryTimeout time.Duration) UnitOfWork {
+	return &unitOfWork{db: db, queryTimeout: queryTimeout}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(ctx, Repositories{
+		Tenders: &tenderRepository{db: instrument(tx, "tender"), queryTimeout: u.queryTimeout},
+		Bids:    &bidRepository{db: instrument(tx, "bid"), queryTimeout: u.queryTimeout},
+		Users:   &userRepository{db: instrument(tx, "user"), queryTimeout: u.queryTimeout},
+		Outbox:  &outboxRepository{db: instrument(tx, "outbox"), queryTimeout: u.queryTimeout},
+	}); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rollbackErr)
+		}
+		return err
+	}
+	return tx.Commit()
+}
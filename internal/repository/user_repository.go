@@ -1,31 +1,64 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	my_errors "tender-service/internal/errors"
 	"tender-service/internal/models"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+// UserRepository looks up active employees only: once deactivated, an
+// employee can no longer be found by ID or username, which keeps them out of
+// every tender and bid flow. ListUsers is the only way to see them.
 type UserRepository interface {
-	FindUserIDByUsername(username string) (string, error)
-	GetUserByID(userID string) (*models.User, error)
-	CheckUserPermission(userID, organizationID string) (bool, error)
-	GetUserByUsername(username string) (*models.User, error)
+	FindUserIDByUsername(ctx context.Context, username string) (string, error)
+	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+	CheckUserPermission(ctx context.Context, userID, organizationID string) (bool, error)
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	CountOrganizationResponsibles(ctx context.Context, organizationID string) (int, error)
+	GetPasswordHash(ctx context.Context, userID string) (string, error)
+	SetPasswordHash(ctx context.Context, userID, hash string) error
+	CreateUser(ctx context.Context, user models.User) (models.User, error)
+	ListUsers(ctx context.Context, filter UserFilter) ([]models.User, error)
+	UpdateUserNames(ctx context.Context, userID string, firstName, lastName *string) (*models.User, error)
+	DeactivateUser(ctx context.Context, userID string) error
+}
+
+// UserFilter selects employees whose username or names contain Search.
+type UserFilter struct {
+	Search          string
+	IncludeInactive bool
+	Limit           int
+	Offset          int
+}
+
+const userColumns = "id, username, COALESCE(first_name, ''), COALESCE(last_name, ''), active, created_at, updated_at"
+
+func scanUser(row interface{ Scan(...interface{}) error }, user *models.User) error {
+	return row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.Active, &user.CreatedAt, &user.UpdatedAt)
 }
 
 type userRepository struct {
-	db *sql.DB
+	db           querier
+	queryTimeout time.Duration
 }
 
-func NewUserRepository(db *sql.DB) UserRepository {
-	return &userRepository{db: db}
+func NewUserRepository(db *sql.DB, queryTimeout time.Duration) UserRepository {
+	return &userRepository{db: instrument(db, "user"), queryTimeout: queryTimeout}
 }
 
-func (r *userRepository) FindUserIDByUsername(username string) (string, error) {
+func (r *userRepository) FindUserIDByUsername(ctx context.Context, username string) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var userID string
-	query := "SELECT id FROM employee WHERE username = $1"
-	err := r.db.QueryRow(query, username).Scan(&userID)
+	query := "SELECT id FROM employee WHERE username = $1 AND active"
+	err := r.db.QueryRowContext(ctx, query, username).Scan(&userID)
 	if err == sql.ErrNoRows {
 		return "", my_errors.ErrUserNotFound
 	} else if err != nil {
@@ -34,10 +67,13 @@ func (r *userRepository) FindUserIDByUsername(username string) (string, error) {
 	return userID, nil
 }
 
-func (r *userRepository) GetUserByID(userID string) (*models.User, error) {
+func (r *userRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var user models.User
-	query := "SELECT id, username, first_name, last_name, created_at, updated_at FROM employee WHERE id = $1"
-	err := r.db.QueryRow(query, userID).Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.CreatedAt, &user.UpdatedAt)
+	query := "SELECT " + userColumns + " FROM employee WHERE id = $1 AND active"
+	err := scanUser(r.db.QueryRowContext(ctx, query, userID), &user)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, my_errors.ErrUserNotFound
@@ -47,22 +83,28 @@ func (r *userRepository) GetUserByID(userID string) (*models.User, error) {
 	return &user, nil
 }
 
-func (r *userRepository) CheckUserPermission(userID, organizationID string) (bool, error) {
+func (r *userRepository) CheckUserPermission(ctx context.Context, userID, organizationID string) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
 	var count int
 	query := "SELECT COUNT(1) FROM organization_responsible WHERE user_id = $1 AND organization_id = $2"
-	err := r.db.QueryRow(query, userID, organizationID).Scan(&count)
+	err := r.db.QueryRowContext(ctx, query, userID, organizationID).Scan(&count)
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
-func (r *userRepository) GetUserByUsername(username string) (*models.User, error) {
-	query := `SELECT id, username, first_name, last_name FROM employee WHERE username = $1`
-	row := r.db.QueryRow(query, username)
+func (r *userRepository) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := "SELECT " + userColumns + " FROM employee WHERE username = $1 AND active"
+	row := r.db.QueryRowContext(ctx, query, username)
 
 	var user models.User
-	err := row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName)
+	err := scanUser(row, &user)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, my_errors.ErrUserNotFound
@@ -72,3 +114,155 @@ func (r *userRepository) GetUserByUsername(username string) (*models.User, error
 
 	return &user, nil
 }
+
+// CountOrganizationResponsibles counts the active responsibles only, since
+// deactivated employees can no longer take part in decisions.
+func (r *userRepository) CountOrganizationResponsibles(ctx context.Context, organizationID string) (int, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var count int
+	query := `
+		SELECT COUNT(1)
+		FROM organization_responsible r
+		JOIN employee e ON e.id = r.user_id
+		WHERE r.organization_id = $1 AND e.active`
+	err := r.db.QueryRowContext(ctx, query, organizationID).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetPasswordHash returns the employee's password hash, or an empty string for
+// employees that cannot log in.
+func (r *userRepository) GetPasswordHash(ctx context.Context, userID string) (string, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	var hash sql.NullString
+	query := "SELECT password_hash FROM employee WHERE id = $1"
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", my_errors.ErrUserNotFound
+	} else if err != nil {
+		return "", err
+	}
+	return hash.String, nil
+}
+
+func (r *userRepository) SetPasswordHash(ctx context.Context, userID, hash string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := "UPDATE employee SET password_hash = $1, updated_at = NOW() WHERE id = $2"
+	result, err := r.db.ExecContext(ctx, query, hash, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return my_errors.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user models.User) (models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO employee (username, first_name, last_name)
+		VALUES ($1, $2, $3)
+		RETURNING ` + userColumns
+	var created models.User
+	err := scanUser(r.db.QueryRowContext(ctx, query, user.Username, user.FirstName, user.LastName), &created)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return models.User{}, my_errors.ErrUsernameTaken
+		}
+		return models.User{}, err
+	}
+	return created, nil
+}
+
+func (r *userRepository) ListUsers(ctx context.Context, filter UserFilter) ([]models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := "SELECT " + userColumns + " FROM employee WHERE TRUE"
+	var args []interface{}
+	if !filter.IncludeInactive {
+		query += " AND active"
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		query += fmt.Sprintf(" AND (username ILIKE $%[1]d OR first_name ILIKE $%[1]d OR last_name ILIKE $%[1]d)", len(args))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY username LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateUserNames changes the names that are not nil and leaves the others.
+func (r *userRepository) UpdateUserNames(ctx context.Context, userID string, firstName, lastName *string) (*models.User, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE employee
+		SET first_name = COALESCE($2, first_name),
+		    last_name = COALESCE($3, last_name),
+		    updated_at = NOW()
+		WHERE id = $1 AND active
+		RETURNING ` + userColumns
+	var user models.User
+	err := scanUser(r.db.QueryRowContext(ctx, query, userID, firstName, lastName), &user)
+	if err == sql.ErrNoRows {
+		return nil, my_errors.ErrUserNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) DeactivateUser(ctx context.Context, userID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := "UPDATE employee SET active = FALSE, updated_at = NOW() WHERE id = $1 AND active"
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return my_errors.ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, subscriptionID string) (models.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context, organizationID string) ([]models.WebhookSubscription, error)
+	// MatchSubscriptions returns the active subscriptions of the
+	// organizations that want events of eventType.
+	MatchSubscriptions(ctx context.Context, organizationIDs []string, eventType string) ([]models.WebhookSubscription, error)
+	// UpdateSubscription stores the subscription's URL, secret, event types,
+	// activity and failure count.
+	UpdateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+
+	// EnqueueDeliveries stores pending deliveries, skipping those already
+	// stored for the same subscription and event.
+	EnqueueDeliveries(ctx context.Context, deliveries ...models.WebhookDelivery) error
+	// ClaimDueDeliveries is OutboxRepository.ClaimDue for the deliveries of
+	// active subscriptions.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+	// MarkDeliverySucceeded records a delivered attempt and resets the
+	// subscription's failure count.
+	MarkDeliverySucceeded(ctx context.Context, deliveryID string, responseStatus int, deliveredAt time.Time) error
+	// MarkDeliveryFailed records a failed attempt. The delivery is retried at
+	// nextAttemptAt unless final. The subscription's failure count grows, and
+	// once it reaches disableAfter the subscription is disabled; the result
+	// reports whether it is.
+	MarkDeliveryFailed(ctx context.Context, deliveryID string, responseStatus int, reason string, nextAttemptAt time.Time, final bool, disableAfter int) (bool, error)
+	ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]models.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db           querier
+	queryTimeout time.Duration
+}
+
+func NewWebhookRepository(db *sql.DB, queryTimeout time.Duration) WebhookRepository {
+	return &webhookRepository{db: instrument(db, "webhook"), queryTimeout: queryTimeout}
+}
+
+const webhookSubscriptionColumns = "id, organization_id, url, secret, event_types, active, consecutive_failures, disabled_at, created_at, updated_at"
+
+func scanWebhookSubscription(row interface{ Scan(...interface{}) error }, subscription *models.WebhookSubscription) error {
+	var eventTypes pq.StringArray
+	var disabledAt sql.NullTime
+	if err := row.Scan(&subscription.ID, &subscription.OrganizationID, &subscription.URL, &subscription.Secret, &eventTypes, &subscription.Active, &subscription.ConsecutiveFailures, &disabledAt, &subscription.CreatedAt, &subscription.UpdatedAt); err != nil {
+		return err
+	}
+	subscription.EventTypes = []string(eventTypes)
+	if subscription.EventTypes == nil {
+		subscription.EventTypes = []string{}
+	}
+	subscription.DisabledAt = nullTimePtr(disabledAt)
+	return nil
+}
+
+const webhookDeliveryColumns = "id, subscription_id, event_id, event_type, payload, status, attempts, COALESCE(response_status, 0), COALESCE(last_error, ''), next_attempt_at, created_at, delivered_at"
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }, delivery *models.WebhookDelivery) error {
+	var payload []byte
+	var deliveredAt sql.NullTime
+	if err := row.Scan(&delivery.ID, &delivery.SubscriptionID, &delivery.EventID, &delivery.EventType, &payload, &delivery.Status, &delivery.Attempts, &delivery.ResponseStatus, &delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt, &deliveredAt); err != nil {
+		return err
+	}
+	delivery.Payload = payload
+	delivery.DeliveredAt = nullTimePtr(deliveredAt)
+	return nil
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO webhook_subscription (organization_id, url, secret, event_types)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + webhookSubscriptionColumns
+	var created models.WebhookSubscription
+	err := scanWebhookSubscription(r.db.QueryRowContext(ctx, query, subscription.OrganizationID, subscription.URL, subscription.Secret, pq.Array(subscription.EventTypes)), &created)
+	return created, err
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, subscriptionID string) (models.WebhookSubscription, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := "SELECT " + webhookSubscriptionColumns + " FROM webhook_subscription WHERE id = $1"
+	var subscription models.WebhookSubscription
+	err := scanWebhookSubscription(r.db.QueryRowContext(ctx, query, subscriptionID), &subscription)
+	if err == sql.ErrNoRows {
+		return models.WebhookSubscription{}, my_errors.ErrWebhookNotFound
+	}
+	return subscription, err
+}
+
+func (r *webhookRepository) ListSubscriptions(ctx context.Context, organizationID string) ([]models.WebhookSubscription, error) {
+	query := "SELECT " + webhookSubscriptionColumns + " FROM webhook_subscription WHERE organization_id = $1 ORDER BY created_at, id"
+	return r.querySubscriptions(ctx, query, organizationID)
+}
+
+func (r *webhookRepository) MatchSubscriptions(ctx context.Context, organizationIDs []string, eventType string) ([]models.WebhookSubscription, error) {
+	query := `
+		SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscription
+		WHERE active AND organization_id::text = ANY($1)
+		AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+		ORDER BY created_at, id`
+	return r.querySubscriptions(ctx, query, pq.Array(organizationIDs), eventType)
+}
+
+func (r *webhookRepository) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]models.WebhookSubscription, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []models.WebhookSubscription{}
+	for rows.Next() {
+		var subscription models.WebhookSubscription
+		if err := scanWebhookSubscription(rows, &subscription); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, subscription models.WebhookSubscription) (models.WebhookSubscription, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		UPDATE webhook_subscription
+		SET url = $2, secret = $3, event_types = $4, active = $5, consecutive_failures = $6, disabled_at = $7, updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + webhookSubscriptionColumns
+	var updated models.WebhookSubscription
+	err := scanWebhookSubscription(r.db.QueryRowContext(ctx, query, subscription.ID, subscription.URL, subscription.Secret, pq.Array(subscription.EventTypes), subscription.Active, subscription.ConsecutiveFailures, subscription.DisabledAt), &updated)
+	if err == sql.ErrNoRows {
+		return models.WebhookSubscription{}, my_errors.ErrWebhookNotFound
+	}
+	return updated, err
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM webhook_subscription WHERE id = $1", subscriptionID)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return my_errors.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) EnqueueDeliveries(ctx context.Context, deliveries ...models.WebhookDelivery) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	for _, delivery := range deliveries {
+		if _, err := r.db.ExecContext(ctx, query, delivery.SubscriptionID, delivery.EventID, delivery.EventType, []byte(delivery.Payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_delivery
+		SET attempts = attempts + 1, next_attempt_at = $2
+		WHERE id IN (
+			SELECT d.id FROM webhook_delivery d
+			JOIN webhook_subscription s ON s.id = d.subscription_id
+			WHERE d.status = 'PENDING' AND d.next_attempt_at <= $1 AND s.active
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		RETURNING ` + webhookDeliveryColumns
+	return r.queryDeliveries(ctx, query, now, now.Add(lease), limit)
+}
+
+func (r *webhookRepository) MarkDeliverySucceeded(ctx context.Context, deliveryID string, responseStatus int, deliveredAt time.Time) error {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	query := `
+		WITH delivered AS (
+			UPDATE webhook_delivery
+			SET status = 'DELIVERED', response_status = $2, last_error = NULL, delivered_at = $3
+			WHERE id = $1
+			RETURNING subscription_id
+		)
+		UPDATE webhook_subscription SET consecutive_failures = 0
+		WHERE id IN (SELECT subscription_id FROM delivered)`
+	_, err := r.db.ExecContext(ctx, query, deliveryID, responseStatus, deliveredAt)
+	return err
+}
+
+func (r *webhookRepository) MarkDeliveryFailed(ctx context.Context, deliveryID string, responseStatus int, reason string, nextAttemptAt time.Time, final bool, disableAfter int) (bool, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	status := models.WebhookDeliveryPending
+	if final {
+		status = models.WebhookDeliveryFailed
+	}
+	query := `
+		WITH failed AS (
+			UPDATE webhook_delivery
+			SET status = $2, response_status = NULLIF($3, 0), last_error = $4, next_attempt_at = $5
+			WHERE id = $1
+			RETURNING subscription_id
+		)
+		UPDATE webhook_subscription s
+		SET consecutive_failures = s.consecutive_failures + 1,
+			active = s.active AND s.consecutive_failures + 1 < $6,
+			disabled_at = CASE WHEN s.active AND s.consecutive_failures + 1 >= $6 THEN NOW() ELSE s.disabled_at END
+		FROM failed
+		WHERE s.id = failed.subscription_id
+		RETURNING NOT s.active`
+	var disabled bool
+	err := r.db.QueryRowContext(ctx, query, deliveryID, status, responseStatus, reason, nextAttemptAt, disableAfter).Scan(&disabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return disabled, err
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID string, limit, offset int) ([]models.WebhookDelivery, error) {
+	query := "SELECT " + webhookDeliveryColumns + " FROM webhook_delivery WHERE subscription_id = $1 ORDER BY created_at DESC, event_id DESC LIMIT $2 OFFSET $3"
+	return r.queryDeliveries(ctx, query, subscriptionID, limit, offset)
+}
+
+func (r *webhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]models.WebhookDelivery, error) {
+	ctx, cancel := withQueryTimeout(ctx, r.queryTimeout)
+	defer cancel()
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &delivery); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
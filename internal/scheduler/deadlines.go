@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+)
+
+// deadlineBatchSize bounds how many tenders one statement closes, so a backlog
+// of expired tenders does not hold row locks for long.
+const deadlineBatchSize = 100
+
+// DeadlineScheduler closes published tenders once their deadlines pass (see
+// models.Tender.ExpiresAt) and stores TenderClosed in the outbox for each of
+// them without an actor. Every replica of the server may run one: the
+// repository skips tenders another replica is already closing.
+type DeadlineScheduler struct {
+	uow      repository.UnitOfWork
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+func NewDeadlineScheduler(uow repository.UnitOfWork, interval time.Duration, logger *slog.Logger) *DeadlineScheduler {
+	return &DeadlineScheduler{uow: uow, interval: interval, logger: logger}
+}
+
+// Run closes expired tenders right away and then every interval until ctx is
+// done.
+func (s *DeadlineScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.CloseExpired(ctx, time.Now()); err != nil {
+			s.logger.ErrorContext(ctx, "Error closing expired tenders", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CloseExpired closes the tenders expired at now, batch by batch, and returns
+// how many it closed.
+func (s *DeadlineScheduler) CloseExpired(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		var closed []models.Tender
+		err := s.uow.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+			var err error
+			closed, err = repos.Tenders.CloseExpiredTenders(ctx, now, deadlineBatchSize)
+			if err != nil {
+				return err
+			}
+			events := make([]event.Event, 0, len(closed))
+			for _, tender := range closed {
+				events = append(events, event.TenderClosed{Meta: event.NewMeta(""), Tender: tender})
+			}
+			return repos.Outbox.Append(ctx, events...)
+		})
+		if err != nil {
+			return total, err
+		}
+		for _, tender := range closed {
+			s.logger.InfoContext(ctx, "Tender closed, its deadline has passed", "tender_id", tender.ID, "deadline", tender.ExpiresAt().Format(time.RFC3339))
+		}
+		total += len(closed)
+		if len(closed) < deadlineBatchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"tender-service/internal/event"
+	"tender-service/internal/logging"
+	"tender-service/internal/models"
+	"tender-service/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeadlineScheduler_CloseExpired(t *testing.T) {
+	store := memory.NewStore()
+	repo := memory.NewTenderRepository(store)
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	create := func(status models.TenderStatus, submission, decision *time.Time) models.Tender {
+		tender, err := repo.CreateTender(context.Background(), models.Tender{
+			Name:               "Delivery",
+			Status:             status,
+			SubmissionDeadline: submission,
+			DecisionDeadline:   decision,
+		})
+		require.NoError(t, err)
+		return tender
+	}
+
+	expired := create(models.Published, &past, nil)
+	awaitingDecision := create(models.Published, &past, &future)
+	decisionPassed := create(models.Published, nil, &past)
+	unpublished := create(models.Created, &past, nil)
+	withoutDeadlines := create(models.Published, nil, nil)
+
+	scheduler := NewDeadlineScheduler(memory.NewUnitOfWork(store), time.Minute, logging.Discard())
+	closed, err := scheduler.CloseExpired(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, closed)
+
+	for tender, status := range map[models.Tender]models.TenderStatus{
+		expired:          models.Closed,
+		awaitingDecision: models.Published,
+		decisionPassed:   models.Closed,
+		unpublished:      models.Created,
+		withoutDeadlines: models.Published,
+	} {
+		stored, err := repo.GetTenderByID(context.Background(), tender.ID)
+		require.NoError(t, err)
+		assert.Equal(t, status, stored.Status, tender.ID)
+	}
+
+	stored, err := repo.GetTenderByID(context.Background(), expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored.Version)
+
+	entries, err := memory.NewOutboxRepository(store).ListOutbox(context.Background(), nil, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		e, err := event.Decode(event.Type(entry.EventType), entry.Payload)
+		require.NoError(t, err)
+		assert.Equal(t, event.TypeTenderClosed, e.Type())
+		assert.Empty(t, e.Metadata().ActorID)
+	}
+
+	closed, err = scheduler.CloseExpired(context.Background(), now)
+	require.NoError(t, err)
+	assert.Zero(t, closed)
+}
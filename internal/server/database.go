@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	pingTimeout = 5 * time.Second
+	// firstRetryDelay doubles after every failed ping, up to maxRetryDelay.
+	firstRetryDelay = 500 * time.Millisecond
+	maxRetryDelay   = 10 * time.Second
+)
+
+// WaitForDB pings db until it answers, at most attempts times with growing
+// pauses in between, so that the server does not start before its database.
+// sql.Open alone never connects.
+func WaitForDB(ctx context.Context, db *sql.DB, attempts int) error {
+	delay := firstRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := PingDB(db)(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("database is unreachable after %d attempts: %w", attempt, err)
+		}
+		log.Printf("Database is unreachable (attempt %d of %d), retrying in %s: %v", attempt, attempts, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// PingDB returns a Check that pings db.
+func PingDB(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+		defer cancel()
+		return db.PingContext(ctx)
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// checkTimeout bounds each readiness check.
+const checkTimeout = 2 * time.Second
+
+// Check reports whether a dependency the server needs is healthy.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Health serves the liveness and readiness probes. The server is live as long
+// as it answers; it is ready while every check passes and it is not shutting
+// down.
+type Health struct {
+	mu       sync.RWMutex
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// AddCheck registers a check the readiness probe reports under name.
+func (h *Health) AddCheck(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// SetDraining makes the readiness probe fail from now on, so that load
+// balancers stop sending requests while the in-flight ones finish.
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+// HealthStatus is the body of both probes. Checks maps each readiness check
+// to "ok" or the reason it failed.
+type HealthStatus struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+	statusDraining    = "draining"
+)
+
+func (h *Health) Live(w http.ResponseWriter, r *http.Request) {
+	writeStatus(w, http.StatusOK, HealthStatus{Status: statusOK})
+}
+
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		writeStatus(w, http.StatusServiceUnavailable, HealthStatus{Status: statusDraining})
+		return
+	}
+
+	h.mu.RLock()
+	checks := h.checks
+	h.mu.RUnlock()
+
+	status := HealthStatus{Status: statusOK, Checks: make(map[string]string, len(checks))}
+	code := http.StatusOK
+	for _, c := range checks {
+		ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+		err := c.check(ctx)
+		cancel()
+
+		if err != nil {
+			log.Printf("Readiness check %s failed: %v", c.name, err)
+			status.Checks[c.name] = err.Error()
+			status.Status = statusUnavailable
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		status.Checks[c.name] = statusOK
+	}
+	writeStatus(w, code, status)
+}
+
+func writeStatus(w http.ResponseWriter, code int, status HealthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Printf("Error encoding health status: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Config holds the HTTP server timeouts. Zero timeouts are left unset.
+type Config struct {
+	Address      string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout bounds how long Run waits for in-flight requests and
+	// background workers once it is asked to stop.
+	ShutdownTimeout time.Duration
+}
+
+// Server serves HTTP requests and runs background workers alongside them, and
+// stops both gracefully.
+type Server struct {
+	http            *http.Server
+	health          *Health
+	shutdownTimeout time.Duration
+	workers         []worker
+}
+
+type worker struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// New returns a server for handler. health, if not nil, stops reporting ready
+// as soon as the server starts shutting down.
+func New(cfg Config, handler http.Handler, health *Health) *Server {
+	return &Server{
+		http: &http.Server{
+			Addr:         cfg.Address,
+			Handler:      handler,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			IdleTimeout:  cfg.IdleTimeout,
+		},
+		health:          health,
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+}
+
+// Go registers a background worker. Run starts it with the server and
+// cancels its context after the last request has drained. run must return
+// once its context is done.
+func (s *Server) Go(name string, run func(ctx context.Context)) {
+	s.workers = append(s.workers, worker{name: name, run: run})
+}
+
+// Run listens on the configured address, or on port 80 when it is empty like
+// http.ListenAndServe, and serves until ctx is done, then shuts down as
+// described in Serve.
+func (s *Server) Run(ctx context.Context) error {
+	address := s.http.Addr
+	if address == "" {
+		address = ":http"
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	log.Printf("Server running at %s", listener.Addr())
+	return s.Serve(ctx, listener)
+}
+
+// Serve serves on listener until ctx is done or serving fails. It then stops
+// accepting connections, waits for in-flight requests, stops the workers and
+// waits for them to return, all within the shutdown timeout.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	var workers sync.WaitGroup
+	for _, w := range s.workers {
+		workers.Add(1)
+		go func(w worker) {
+			defer workers.Done()
+			w.run(workersCtx)
+			log.Printf("Worker %s stopped", w.name)
+		}(w)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(listener)
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		log.Printf("Shutting down, draining in-flight requests")
+	case err = <-serveErr:
+		log.Printf("Server stopped serving: %v", err)
+	}
+	if s.health != nil {
+		s.health.SetDraining()
+	}
+
+	shutdownCtx := context.Background()
+	if s.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		shutdownCtx, cancel = context.WithTimeout(shutdownCtx, s.shutdownTimeout)
+		defer cancel()
+	}
+	if shutdownErr := s.http.Shutdown(shutdownCtx); shutdownErr != nil {
+		log.Printf("Error draining requests: %v", shutdownErr)
+		s.http.Close()
+	}
+
+	stopWorkers()
+	stopped := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		log.Printf("Background workers did not stop within the shutdown timeout")
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth_Ready(t *testing.T) {
+	health := NewHealth()
+	var dbErr error
+	health.AddCheck("database", func(ctx context.Context) error { return dbErr })
+
+	probe := func(handler http.HandlerFunc) (int, HealthStatus) {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+		var status HealthStatus
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&status))
+		return rr.Code, status
+	}
+
+	code, status := probe(health.Ready)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatus{Status: "ok", Checks: map[string]string{"database": "ok"}}, status)
+
+	dbErr = errors.New("connection refused")
+	code, status = probe(health.Ready)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatus{Status: "unavailable", Checks: map[string]string{"database": "connection refused"}}, status)
+
+	code, _ = probe(health.Live)
+	assert.Equal(t, http.StatusOK, code, "a failing dependency does not make the server dead")
+
+	dbErr = nil
+	health.SetDraining()
+	code, status = probe(health.Ready)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "draining", status.Status)
+}
+
+func TestServer_DrainsRequestsAndStopsWorkers(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("done"))
+	})
+
+	health := NewHealth()
+	srv := New(Config{ShutdownTimeout: 5 * time.Second}, handler, health)
+	workerStopped := make(chan struct{})
+	srv.Go("test", func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerStopped)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, listener) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+	select {
+	case <-workerStopped:
+		t.Fatal("worker stopped before the in-flight request finished")
+	case <-served:
+		t.Fatal("server returned before the in-flight request finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	got := <-response
+	require.NoError(t, got.err)
+	assert.Equal(t, "done", got.body)
+	require.NoError(t, <-served)
+	<-workerStopped
+
+	rr := httptest.NewRecorder()
+	health.Ready(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"tender-service/internal/auth"
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/repository"
+)
+
+type AuthService interface {
+	IssueToken(ctx context.Context, username, password string) (string, time.Time, error)
+}
+
+type authService struct {
+	userRepo repository.UserRepository
+	tokens   *auth.TokenManager
+	logger   *slog.Logger
+}
+
+func NewAuthService(userRepo repository.UserRepository, tokens *auth.TokenManager, logger *slog.Logger) AuthService {
+	return &authService{userRepo: userRepo, tokens: tokens, logger: logger}
+}
+
+// IssueToken exchanges an employee's credentials for a bearer token. Unknown
+// employees, employees without a password and wrong passwords all fail the
+// same way.
+func (s *authService) IssueToken(ctx context.Context, username, password string) (string, time.Time, error) {
+	user, err := s.userRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrUserNotFound) {
+			s.logger.InfoContext(ctx, "Unknown username", "username", username)
+			return "", time.Time{}, my_errors.ErrInvalidCredentials
+		}
+		return "", time.Time{}, err
+	}
+
+	hash, err := s.userRepo.GetPasswordHash(ctx, user.ID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if hash == "" || !auth.CheckPassword(hash, password) {
+		s.logger.InfoContext(ctx, "Invalid credentials", "username", username)
+		return "", time.Time{}, my_errors.ErrInvalidCredentials
+	}
+
+	return s.tokens.Issue(*user)
+}
@@ -1,314 +1,727 @@
 package service
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"log"
+	"log/slog"
+	"tender-service/internal/auth"
 	my_errors "tender-service/internal/errors"
+	"tender-service/internal/event"
 	"tender-service/internal/models"
 	"tender-service/internal/repository"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type BidService interface {
-	CreateBid(description, tenderID, organizationID, userID string, authorType models.BidAuthorType) (*models.Bid, error)
-	GetBidsByTenderID(tenderID, username string, limit, offset int) ([]models.Bid, error)
-	GetUserBids(userID string, limit, offset int) ([]models.Bid, error)
-	GetBidStatus(bidID string, username string) (models.BidStatus, error)
-	UpdateBidStatus(bidID, status, username string) error
-	EditBid(bidID, username string, updates map[string]interface{}) error
-	SubmitBidFeedback(bidID, username, feedback string) (*models.Bid, error)
+	CreateBid(ctx context.Context, description, tenderID, organizationID string, authorType models.BidAuthorType) (*models.Bid, error)
+	GetBidsByTenderID(ctx context.Context, tenderID string, options repository.QueryOptions) ([]models.Bid, error)
+	GetUserBids(ctx context.Context, options repository.QueryOptions) ([]models.Bid, error)
+	SearchBids(ctx context.Context, query string, options repository.QueryOptions) ([]models.BidSearchResult, error)
+	// GetBidStatus returns the status of the bid and its current version.
+	GetBidStatus(ctx context.Context, bidID string) (models.BidStatus, int, error)
+	UpdateBidStatus(ctx context.Context, bidID, status string, expectedVersion int) (*models.Bid, error)
+	EditBid(ctx context.Context, bidID string, expectedVersion int, updates map[string]interface{}) (*models.Bid, error)
+	SubmitBidFeedback(ctx context.Context, bidID, feedback string) (*models.Bid, error)
+	SubmitBidDecision(ctx context.Context, bidID string, decision models.BidDecision) (*models.Bid, error)
+	RollbackBid(ctx context.Context, bidID string, version int, expectedVersion int) (*models.Bid, error)
+	GetBidReviews(ctx context.Context, tenderID, authorUsername string, options repository.QueryOptions) ([]models.BidReview, error)
 }
 
 type bidService struct {
 	repo       repository.BidRepository
 	tenderRepo repository.TenderRepository
 	userRepo   repository.UserRepository
+	uow        repository.UnitOfWork
+	logger     *slog.Logger
 }
 
-func NewBidService(repo repository.BidRepository, tenderRepo repository.TenderRepository, userRepo repository.UserRepository) BidService {
-	return &bidService{repo: repo, tenderRepo: tenderRepo, userRepo: userRepo}
+// NewBidService builds the service. Changes run in units of work so that the
+// events describing them are stored in the outbox atomically.
+func NewBidService(repo repository.BidRepository, tenderRepo repository.TenderRepository, userRepo repository.UserRepository, uow repository.UnitOfWork, logger *slog.Logger) BidService {
+	return &bidService{repo: repo, tenderRepo: tenderRepo, userRepo: userRepo, uow: uow, logger: logger}
 }
 
-func (s *bidService) CreateBid(description, tenderID, organizationID, userID string, authorType models.BidAuthorType) (*models.Bid, error) {
+// editableBidFields are the bid columns an author may change through EditBid.
+var editableBidFields = map[string]bool{
+	"description": true,
+}
+
+// isBidAuthor reports whether the user acts as the bid's author. Bids authored
+// on behalf of an organization belong to all of its responsibles.
+func (s *bidService) isBidAuthor(ctx context.Context, bid *models.Bid, userID string) (bool, error) {
+	if bid.AuthorType == models.BidAuthorTypeOrganization {
+		return s.userRepo.CheckUserPermission(ctx, userID, bid.OrganizationID)
+	}
+	return bid.UserID == userID, nil
+}
+
+// isTenderResponsible reports whether the user is a responsible of the
+// organization that owns the bid's tender.
+func (s *bidService) isTenderResponsible(ctx context.Context, bid *models.Bid, userID string) (bool, error) {
+	tender, err := s.tenderRepo.GetTenderByID(ctx, bid.TenderID)
+	if err != nil {
+		return false, err
+	}
+	return s.userRepo.CheckUserPermission(ctx, userID, tender.OrganizationID)
+}
+
+// canViewBid reports whether the user may see the bid: authors always can,
+// the tender's responsibles only once the bid has been published.
+func (s *bidService) canViewBid(ctx context.Context, bid *models.Bid, userID string) (bool, error) {
+	isAuthor, err := s.isBidAuthor(ctx, bid, userID)
+	if err != nil || isAuthor {
+		return isAuthor, err
+	}
+	if !bid.Status.IsVisibleToTender() {
+		return false, nil
+	}
+	return s.isTenderResponsible(ctx, bid, userID)
+}
+
+// caller resolves the employee making the request.
+func (s *bidService) caller(ctx context.Context) (*models.User, error) {
+	return auth.Caller(ctx, s.userRepo)
+}
+
+func (s *bidService) CreateBid(ctx context.Context, description, tenderID, organizationID string, authorType models.BidAuthorType) (*models.Bid, error) {
 	if _, err := uuid.Parse(tenderID); err != nil {
-		log.Printf("Invalid tenderID format: %s", tenderID)
+		s.logger.InfoContext(ctx, "Invalid tender ID", "tender_id", tenderID)
 		return nil, my_errors.ErrBadRequest
 	}
 
 	if _, err := uuid.Parse(organizationID); err != nil {
-		log.Printf("Invalid organizationID format: %s", organizationID)
+		s.logger.InfoContext(ctx, "Invalid organization ID", "organization_id", organizationID)
 		return nil, my_errors.ErrBadRequest
 	}
 
-	if _, err := uuid.Parse(userID); err != nil {
-		log.Printf("Invalid userID format: %s", userID)
+	if authorType != models.BidAuthorTypeUser && authorType != models.BidAuthorTypeOrganization {
+		s.logger.InfoContext(ctx, "Invalid author type", "author_type", authorType)
 		return nil, my_errors.ErrBadRequest
 	}
 
-	_, err := s.userRepo.GetUserByID(userID)
+	author, err := s.caller(ctx)
 	if err != nil {
-		if errors.Is(err, my_errors.ErrUserNotFound) {
-			log.Printf("User with ID %s not found", userID)
-			return nil, my_errors.ErrUnauthorized
-		}
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
 		return nil, err
 	}
+	userID := author.ID
+
+	// The checks run in the same unit of work as the insert, and the tender
+	// stays locked until it commits, so the tender cannot close in between.
+	return inTransaction(ctx, s.uow, func(repos repository.Repositories) (*models.Bid, error) {
+		tender, err := repos.Tenders.GetTenderForShare(ctx, tenderID)
+		if err != nil {
+			if errors.Is(err, my_errors.ErrTenderNotFound) {
+				s.logger.InfoContext(ctx, "Tender not found", "tender_id", tenderID)
+			}
+			return nil, err
+		}
 
-	_, err = s.tenderRepo.GetTenderByID(tenderID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			log.Printf("Error finding tender with ID: %s, error: %v", tenderID, "tender not found")
-			return nil, my_errors.ErrTenderNotFound
+		if tender.Status != models.Published {
+			s.logger.InfoContext(ctx, "Tender is not published", "tender_id", tenderID, "status", tender.Status)
+			return nil, my_errors.ErrTenderNotPublished
+		}
+
+		if !tender.AcceptsBidsAt(time.Now()) {
+			s.logger.InfoContext(ctx, "Tender no longer accepts bids", "tender_id", tenderID, "submission_deadline", tender.SubmissionDeadline)
+			return nil, my_errors.ErrSubmissionClosed
+		}
+
+		hasPermission, err := repos.Users.CheckUserPermission(ctx, userID, organizationID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !hasPermission {
+			s.logger.InfoContext(ctx, "Caller may not bid for organization", "user_id", userID, "organization_id", organizationID)
+			return nil, my_errors.ErrForbidden
+		}
+
+		bid := &models.Bid{
+			Description:    description,
+			TenderID:       tenderID,
+			OrganizationID: organizationID,
+			UserID:         userID,
+			AuthorType:     authorType,
+			Status:         models.BidStatusCreated,
 		}
+
+		createdBid, err := repos.Bids.CreateBid(ctx, bid)
+		if err != nil {
+			return nil, err
+		}
+		return createdBid, repos.Outbox.Append(ctx, event.BidCreated{Meta: event.NewMeta(userID), Bid: *createdBid})
+	})
+}
+
+func (s *bidService) GetUserBids(ctx context.Context, options repository.QueryOptions) ([]models.Bid, error) {
+	if err := validateStatuses(options.Statuses, models.ParseBidStatus); err != nil {
 		return nil, err
 	}
 
-	hasPermission, err := s.userRepo.CheckUserPermission(userID, organizationID)
+	user, err := s.caller(ctx)
 	if err != nil {
+		s.logger.WarnContext(ctx, "Error resolving caller", "error", err)
 		return nil, err
 	}
+	s.logger.DebugContext(ctx, "Listing caller bids", "username", user.Username, "limit", options.Limit, "offset", options.Offset)
 
-	if !hasPermission {
-		log.Printf("User %s does not have permission to bid for organization %s", userID, organizationID)
-		return nil, my_errors.ErrForbidden
-	}
-
-	bid := &models.Bid{
-		Description:    description,
-		TenderID:       tenderID,
-		OrganizationID: organizationID,
-		UserID:         userID,
-		AuthorType:     authorType,
-		Status:         models.BidStatusCreated,
-	}
-
-	createdBid, err := s.repo.CreateBid(bid)
+	bids, err := s.repo.GetBidsByUserID(ctx, user.ID, options)
 	if err != nil {
+		s.logger.WarnContext(ctx, "Error listing caller bids", "username", user.Username, "error", err)
 		return nil, err
 	}
 
-	return createdBid, nil
+	s.logger.DebugContext(ctx, "Listed caller bids", "username", user.Username)
+	return bids, nil
 }
 
-func (s *bidService) GetUserBids(username string, limit, offset int) ([]models.Bid, error) {
-	log.Printf("GetUserBids called for username: %s, limit: %d, offset: %d", username, limit, offset)
+// SearchBids finds the bids matching query among those the caller may view
+// (see canViewBid), best matches first.
+func (s *bidService) SearchBids(ctx context.Context, query string, options repository.QueryOptions) ([]models.BidSearchResult, error) {
+	if err := validateSearchQuery(query); err != nil {
+		return nil, err
+	}
+	if err := validateStatuses(options.Statuses, models.ParseBidStatus); err != nil {
+		return nil, err
+	}
 
-	user, err := s.userRepo.GetUserByUsername(username)
+	user, err := s.caller(ctx)
 	if err != nil {
-		log.Printf("Error retrieving user by username: %s, error: %v", username, err)
-		if errors.Is(err, my_errors.ErrUserNotFound) {
-			return nil, my_errors.ErrUserNotFound
-		}
+		s.logger.WarnContext(ctx, "Error resolving caller", "error", err)
 		return nil, err
 	}
 
-	bids, err := s.repo.GetBidsByUserID(user.ID, limit, offset)
+	organizationIDs, err := s.tenderRepo.GetUserOrganizationIDs(ctx, user.ID)
 	if err != nil {
-		log.Printf("Error retrieving bids for user: %s, error: %v", username, err)
 		return nil, err
 	}
 
-	log.Printf("Successfully retrieved bids for user: %s", username)
-	return bids, nil
+	results, err := s.repo.SearchBids(ctx, query, repository.BidVisibility{
+		UserID:          user.ID,
+		OrganizationIDs: organizationIDs,
+		TenderStatuses:  models.TenderVisibleBidStatuses,
+	}, options)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error searching bids", "username", user.Username, "error", err)
+		return nil, err
+	}
+	return results, nil
 }
 
-func (s *bidService) GetBidsByTenderID(tenderID, username string, limit, offset int) ([]models.Bid, error) {
-
-	log.Printf("GetBidsByTenderID called with tenderID: %s, username: %s, limit: %d, offset: %d", tenderID, username, limit, offset)
+func (s *bidService) GetBidsByTenderID(ctx context.Context, tenderID string, options repository.QueryOptions) ([]models.Bid, error) {
+	if err := validateStatuses(options.Statuses, models.ParseBidStatus); err != nil {
+		return nil, err
+	}
 
-	user, err := s.userRepo.GetUserByUsername(username)
+	user, err := s.caller(ctx)
 	if err != nil {
-		if errors.Is(err, my_errors.ErrUserNotFound) {
-			log.Printf("User not found: %s", username)
-		} else {
-			log.Printf("Error retrieving user by username: %s, error: %v", username, err)
-		}
-		return nil, my_errors.ErrUserNotFound
+		s.logger.WarnContext(ctx, "Error resolving caller", "error", err)
+		return nil, err
 	}
-	log.Printf("User %s found with ID: %s", username, user.ID)
+	s.logger.DebugContext(ctx, "Listing tender bids", "tender_id", tenderID, "username", user.Username, "limit", options.Limit, "offset", options.Offset)
 
 	_, err = uuid.Parse(tenderID)
 	if err != nil {
-		log.Printf("Invalid tender ID format: %s", tenderID)
+		s.logger.InfoContext(ctx, "Invalid tender ID", "tender_id", tenderID)
 		return nil, my_errors.ErrBadRequest
 	}
 
-	tender, err := s.tenderRepo.GetTenderByID(tenderID)
+	tender, err := s.tenderRepo.GetTenderByID(ctx, tenderID)
 	if err != nil {
 		if errors.Is(err, my_errors.ErrTenderNotFound) {
-			log.Printf("Tender not found: %s", tenderID)
+			s.logger.InfoContext(ctx, "Tender not found", "tender_id", tenderID)
 			return nil, my_errors.ErrTenderNotFound
 		}
-		log.Printf("Error retrieving tender by ID: %s, error: %v", tenderID, err)
+		s.logger.WarnContext(ctx, "Error fetching tender", "tender_id", tenderID, "error", err)
 		return nil, err
 	}
-	log.Printf("Tender %s found, associated with organization %s", tenderID, tender.OrganizationID)
 
-	hasPermission, err := s.userRepo.CheckUserPermission(user.ID, tender.OrganizationID)
+	hasPermission, err := s.userRepo.CheckUserPermission(ctx, user.ID, tender.OrganizationID)
 	if err != nil {
-		log.Printf("Error checking user permission for user %s and organization %s: %v", user.ID, tender.OrganizationID, err)
+		s.logger.WarnContext(ctx, "Error checking caller permission", "user_id", user.ID, "organization_id", tender.OrganizationID, "error", err)
 		return nil, err
 	}
 	if !hasPermission {
-		log.Printf("User %s does not have permission to access organization %s", user.ID, tender.OrganizationID)
+		s.logger.InfoContext(ctx, "Caller may not access organization", "user_id", user.ID, "organization_id", tender.OrganizationID)
 		return nil, my_errors.ErrForbidden
 	}
-	log.Printf("User %s has permission to access organization %s", user.ID, tender.OrganizationID)
 
-	bids, err := s.repo.GetBidsByTenderID(tenderID, limit, offset)
+	bids, err := s.repo.GetBidsByTenderID(ctx, tenderID, models.TenderVisibleBidStatuses, options)
 	if err != nil {
 		if errors.Is(err, my_errors.ErrTenderNotFound) {
-			log.Printf("Tender not found: %s", tenderID)
+			s.logger.InfoContext(ctx, "Tender not found", "tender_id", tenderID)
 			return nil, my_errors.ErrTenderNotFound
 		}
-		log.Printf("Error retrieving bids for tender %s: %v", tenderID, err)
+		s.logger.WarnContext(ctx, "Error listing tender bids", "tender_id", tenderID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("Retrieved %d bids for tender %s", len(bids), tenderID)
+	s.logger.DebugContext(ctx, "Listed tender bids", "count", len(bids), "tender_id", tenderID)
 	return bids, nil
 }
 
-func (s *bidService) GetBidStatus(bidID string, username string) (models.BidStatus, error) {
-	log.Printf("GetBidStatus: Parsing bidID=%s", bidID)
+func (s *bidService) GetBidStatus(ctx context.Context, bidID string) (models.BidStatus, int, error) {
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		log.Printf("GetBidStatus: Invalid bidID format: %s", bidID)
-		return "", my_errors.ErrBadRequest
+		s.logger.InfoContext(ctx, "Invalid bid ID", "bid_id", bidID)
+		return "", 0, my_errors.ErrBadRequest
 	}
 
-	log.Printf("GetBidStatus: Fetching bid by ID=%s", bidID)
-	bid, err := s.repo.GetBidByID(bidID)
+	s.logger.DebugContext(ctx, "Fetching bid", "bid_id", bidID)
+	bid, err := s.repo.GetBidByID(ctx, bidID)
 	if err != nil {
-		log.Printf("GetBidStatus: Error fetching bid: %v", err)
-		return "", err
+		s.logger.WarnContext(ctx, "Error fetching bid", "bid_id", bidID, "error", err)
+		return "", 0, err
 	}
 
-	log.Printf("GetBidStatus: Fetching user by username=%s", username)
-	user, err := s.userRepo.GetUserByUsername(username)
+	user, err := s.caller(ctx)
 	if err != nil {
-		log.Printf("GetBidStatus: User not found for username=%s", username)
-		return "", my_errors.ErrUserNotFound
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return "", 0, err
 	}
 
-	log.Printf("GetBidStatus: Checking access rights for username=%s on bidID=%s", username, bidID)
-	if bid.UserID != user.ID {
-		log.Printf("GetBidStatus: Access denied for username=%s on bidID=%s", username, bidID)
-		return "", my_errors.ErrForbidden
+	s.logger.DebugContext(ctx, "Checking access rights", "username", user.Username, "bid_id", bidID)
+	canView, err := s.canViewBid(ctx, bid, user.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return "", 0, err
+	}
+	if !canView {
+		s.logger.InfoContext(ctx, "Access denied", "username", user.Username, "bid_id", bidID)
+		return "", 0, my_errors.ErrForbidden
 	}
 
-	log.Printf("GetBidStatus: Returning status=%s for bidID=%s", bid.Status, bidID)
-	return bid.Status, nil
+	s.logger.DebugContext(ctx, "Returning bid status", "bid_id", bidID, "status", bid.Status)
+	return bid.Status, bid.Version, nil
 }
 
-func (s *bidService) UpdateBidStatus(bidID, status, username string) error {
-	log.Printf("UpdateBidStatus: Parsing bidID=%s", bidID)
+func (s *bidService) UpdateBidStatus(ctx context.Context, bidID, status string, expectedVersion int) (*models.Bid, error) {
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		log.Printf("UpdateBidStatus: Invalid bidID format: %s", bidID)
-		return my_errors.ErrBadRequest
+		s.logger.InfoContext(ctx, "Invalid bid ID", "bid_id", bidID)
+		return nil, my_errors.ErrBadRequest
+	}
+
+	s.logger.DebugContext(ctx, "Fetching bid", "bid_id", bidID)
+	bid, err := s.repo.GetBidByID(ctx, bidID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error fetching bid", "bid_id", bidID, "error", err)
+		return nil, err
 	}
 
-	log.Printf("UpdateBidStatus: Fetching bid by ID=%s", bidID)
-	bid, err := s.repo.GetBidByID(bidID)
+	user, err := s.caller(ctx)
 	if err != nil {
-		log.Printf("UpdateBidStatus: Error fetching bid: %v", err)
-		return err
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return nil, err
 	}
 
-	log.Printf("UpdateBidStatus: Fetching user by username=%s", username)
-	user, err := s.userRepo.GetUserByUsername(username)
+	s.logger.DebugContext(ctx, "Checking access rights", "username", user.Username, "bid_id", bidID)
+	isAuthor, err := s.isBidAuthor(ctx, bid, user.ID)
 	if err != nil {
-		log.Printf("UpdateBidStatus: User not found for username=%s", username)
-		return my_errors.ErrUserNotFound
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return nil, err
+	}
+	if !isAuthor {
+		s.logger.InfoContext(ctx, "Access denied", "username", user.Username, "bid_id", bidID)
+		return nil, my_errors.ErrForbidden
 	}
 
-	log.Printf("UpdateBidStatus: Checking access rights for username=%s on bidID=%s", username, bidID)
-	if bid.UserID != user.ID {
-		log.Printf("UpdateBidStatus: Access denied for username=%s on bidID=%s", username, bidID)
-		return my_errors.ErrForbidden
+	bidStatus, err := models.ParseBidStatus(status)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Invalid bid status", "status", status)
+		return nil, my_errors.ErrInvalidBidStatus
 	}
 
-	bidStatus := models.BidStatus(status)
+	if bidStatus.IsDecisionOutcome() || !bid.Status.CanTransitionTo(bidStatus) {
+		s.logger.InfoContext(ctx, "Bid status transition not allowed", "bid_id", bidID, "from", bid.Status, "to", bidStatus)
+		return nil, my_errors.ErrInvalidBidTransition
+	}
 
-	log.Printf("UpdateBidStatus: Updating bid status to %s", bidStatus)
-	err = s.repo.UpdateBidStatus(bidID, bidStatus)
+	if err := checkExpectedVersion(expectedVersion, bid.Version); err != nil {
+		s.logger.InfoContext(ctx, "Version conflict", "bid_id", bidID, "expected_version", expectedVersion, "version", bid.Version)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Updating bid status", "bid_id", bidID, "status", bidStatus)
+	updatedBid, err := inTransaction(ctx, s.uow, func(repos repository.Repositories) (*models.Bid, error) {
+		updatedBid, err := repos.Bids.UpdateBidStatus(ctx, bidID, bidStatus, bid.Version)
+		if err != nil || updatedBid.Status == bid.Status {
+			return updatedBid, err
+		}
+		return updatedBid, repos.Outbox.Append(ctx, event.BidStatusChanged{Meta: event.NewMeta(user.ID), Bid: *updatedBid, PreviousStatus: bid.Status})
+	})
 	if err != nil {
-		log.Printf("UpdateBidStatus: Error updating bid status: %v", err)
-		return err
+		s.logger.WarnContext(ctx, "Error updating bid status", "error", err)
+		return nil, err
 	}
 
-	return nil
+	return updatedBid, nil
 }
 
-func (s *bidService) EditBid(bidID, username string, updates map[string]interface{}) error {
-	log.Printf("EditBid: Parsing bidID=%s", bidID)
+func (s *bidService) EditBid(ctx context.Context, bidID string, expectedVersion int, updates map[string]interface{}) (*models.Bid, error) {
 	_, err := uuid.Parse(bidID)
 	if err != nil {
-		log.Printf("EditBid: Invalid bidID format: %s", bidID)
-		return my_errors.ErrBadRequest
+		s.logger.InfoContext(ctx, "Invalid bid ID", "bid_id", bidID)
+		return nil, my_errors.ErrBadRequest
 	}
 
-	log.Printf("EditBid: Fetching bid by ID=%s", bidID)
-	bid, err := s.repo.GetBidByID(bidID)
+	s.logger.DebugContext(ctx, "Fetching bid", "bid_id", bidID)
+	bid, err := s.repo.GetBidByID(ctx, bidID)
 	if err != nil {
-		log.Printf("EditBid: Error fetching bid: %v", err)
-		return err
+		s.logger.WarnContext(ctx, "Error fetching bid", "bid_id", bidID, "error", err)
+		return nil, err
 	}
 
-	log.Printf("EditBid: Fetching user by username=%s", username)
-	user, err := s.userRepo.GetUserByUsername(username)
+	user, err := s.caller(ctx)
 	if err != nil {
-		log.Printf("EditBid: User not found for username=%s", username)
-		return my_errors.ErrUserNotFound
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Checking access rights", "username", user.Username, "bid_id", bidID)
+	isAuthor, err := s.isBidAuthor(ctx, bid, user.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return nil, err
+	}
+	if !isAuthor {
+		s.logger.InfoContext(ctx, "Access denied", "username", user.Username, "bid_id", bidID)
+		return nil, my_errors.ErrForbidden
+	}
+
+	if !bid.Status.IsEditable() {
+		s.logger.InfoContext(ctx, "Bid cannot be edited", "bid_id", bidID, "status", bid.Status)
+		return nil, my_errors.ErrBidNotEditable
 	}
 
-	log.Printf("EditBid: Checking access rights for username=%s on bidID=%s", username, bidID)
-	if bid.UserID != user.ID {
-		log.Printf("EditBid: Access denied for username=%s on bidID=%s", username, bidID)
-		return my_errors.ErrForbidden
+	for field := range updates {
+		if !editableBidFields[field] {
+			s.logger.InfoContext(ctx, "Field cannot be edited", "field", field)
+			return nil, my_errors.ErrBadRequest
+		}
+	}
+
+	if err := checkExpectedVersion(expectedVersion, bid.Version); err != nil {
+		s.logger.InfoContext(ctx, "Version conflict", "bid_id", bidID, "expected_version", expectedVersion, "version", bid.Version)
+		return nil, err
 	}
 
-	log.Printf("EditBid: Applying updates to bid")
-	err = s.repo.EditBid(bidID, updates)
+	s.logger.DebugContext(ctx, "Applying bid updates", "bid_id", bidID)
+	updatedBid, err := s.repo.EditBid(ctx, bidID, updates, bid.Version)
 	if err != nil {
-		log.Printf("EditBid: Error updating bid: %v", err)
-		return err
+		s.logger.WarnContext(ctx, "Error updating bid", "error", err)
+		return nil, err
 	}
 
-	return nil
+	return updatedBid, nil
 }
 
-func (s *bidService) SubmitBidFeedback(bidID, username, feedback string) (*models.Bid, error) {
-	log.Printf("SubmitBidFeedback: Fetching bid by ID=%s", bidID)
+func (s *bidService) SubmitBidFeedback(ctx context.Context, bidID, feedback string) (*models.Bid, error) {
+	s.logger.DebugContext(ctx, "Fetching bid", "bid_id", bidID)
 
-	bid, err := s.repo.GetBidByID(bidID)
+	bid, err := s.repo.GetBidByID(ctx, bidID)
 	if err != nil {
-		log.Printf("SubmitBidFeedback: Error fetching bid: %v", err)
+		s.logger.WarnContext(ctx, "Error fetching bid", "bid_id", bidID, "error", err)
 		return nil, err
 	}
 
-	log.Printf("SubmitBidFeedback: Fetching user by username=%s", username)
-	user, err := s.userRepo.GetUserByUsername(username)
+	user, err := s.caller(ctx)
 	if err != nil {
-		log.Printf("SubmitBidFeedback: User not found for username=%s", username)
-		return nil, my_errors.ErrUserNotFound
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return nil, err
 	}
 
-	if bid.UserID != user.ID {
-		log.Printf("SubmitBidFeedback: Access denied for username=%s on bidID=%s", username, bidID)
+	s.logger.DebugContext(ctx, "Checking access rights", "username", user.Username, "bid_id", bidID)
+	isResponsible, err := s.isTenderResponsible(ctx, bid, user.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return nil, err
+	}
+	if !isResponsible || !bid.Status.IsVisibleToTender() {
+		s.logger.InfoContext(ctx, "Access denied", "username", user.Username, "bid_id", bidID)
 		return nil, my_errors.ErrForbidden
 	}
 
-	log.Printf("SubmitBidFeedback: Adding feedback for bidID=%s", bidID)
-	err = s.repo.AddBidFeedback(bidID, feedback)
+	s.logger.DebugContext(ctx, "Adding feedback", "bid_id", bidID)
+	err = s.uow.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		if err := repos.Bids.AddBidFeedback(ctx, bidID, feedback); err != nil {
+			return err
+		}
+		return repos.Outbox.Append(ctx, event.FeedbackAdded{Meta: event.NewMeta(user.ID), Bid: *bid, Feedback: feedback})
+	})
 	if err != nil {
-		log.Printf("SubmitBidFeedback: Error adding feedback: %v", err)
+		s.logger.WarnContext(ctx, "Error adding feedback", "error", err)
 		return nil, err
 	}
 
-	log.Printf("SubmitBidFeedback: Feedback successfully added for bidID=%s", bidID)
+	s.logger.InfoContext(ctx, "Feedback added", "bid_id", bidID)
 	return bid, nil
 }
+
+func (s *bidService) SubmitBidDecision(ctx context.Context, bidID string, decision models.BidDecision) (*models.Bid, error) {
+	_, err := uuid.Parse(bidID)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Invalid bid ID", "bid_id", bidID)
+		return nil, my_errors.ErrBadRequest
+	}
+
+	s.logger.DebugContext(ctx, "Fetching bid", "bid_id", bidID)
+	bid, err := s.repo.GetBidByID(ctx, bidID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error fetching bid", "bid_id", bidID, "error", err)
+		return nil, err
+	}
+
+	user, err := s.caller(ctx)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Fetching tender", "tender_id", bid.TenderID)
+	tender, err := s.tenderRepo.GetTenderByID(ctx, bid.TenderID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error fetching tender", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Checking access rights", "username", user.Username, "organization_id", tender.OrganizationID)
+	hasPermission, err := s.userRepo.CheckUserPermission(ctx, user.ID, tender.OrganizationID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error checking user permission", "error", err)
+		return nil, err
+	}
+	if !hasPermission {
+		s.logger.InfoContext(ctx, "Access denied", "username", user.Username, "bid_id", bidID)
+		return nil, my_errors.ErrForbidden
+	}
+
+	if !bid.Status.CanTransitionTo(models.BidStatusApproved) {
+		s.logger.InfoContext(ctx, "Bid cannot be decided", "bid_id", bidID, "status", bid.Status)
+		return nil, my_errors.ErrBidDecisionNotAllowed
+	}
+
+	// The decision, the outcome it brings about and their events are stored
+	// together, so a failure halfway leaves no decision without its outcome.
+	meta := event.NewMeta(user.ID)
+	return inTransaction(ctx, s.uow, func(repos repository.Repositories) (*models.Bid, error) {
+		// Decisions on the bids of a tender run one at a time: each locks the
+		// tender, then the bid, and checks them again, so that no two bids
+		// get approved and every decision counts the approvals made before.
+		tender, err := repos.Tenders.GetTenderForUpdate(ctx, bid.TenderID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Error locking tender", "tender_id", bid.TenderID, "error", err)
+			return nil, err
+		}
+		bid, err := repos.Bids.GetBidForUpdate(ctx, bidID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Error locking bid", "bid_id", bidID, "error", err)
+			return nil, err
+		}
+		if !bid.Status.CanTransitionTo(models.BidStatusApproved) {
+			s.logger.InfoContext(ctx, "Bid cannot be decided", "bid_id", bidID, "status", bid.Status)
+			return nil, my_errors.ErrBidDecisionNotAllowed
+		}
+		if tender.Status == models.Closed {
+			s.logger.InfoContext(ctx, "Tender is closed", "tender_id", tender.ID)
+			return nil, my_errors.ErrTenderAlreadyDecided
+		}
+		approvedBids, err := repos.Bids.CountTenderBids(ctx, tender.ID, []models.BidStatus{models.BidStatusApproved})
+		if err != nil {
+			s.logger.WarnContext(ctx, "Error counting approved bids", "error", err)
+			return nil, err
+		}
+		if approvedBids > 0 {
+			s.logger.InfoContext(ctx, "Tender already has an approved bid", "tender_id", tender.ID)
+			return nil, my_errors.ErrTenderAlreadyDecided
+		}
+
+		s.logger.DebugContext(ctx, "Saving decision", "bid_id", bidID, "decision", decision)
+		if err := repos.Bids.AddBidDecision(ctx, bidID, user.ID, decision); err != nil {
+			s.logger.WarnContext(ctx, "Error saving decision", "error", err)
+			return nil, err
+		}
+		if err := repos.Outbox.Append(ctx, event.BidDecisionSubmitted{Meta: meta, Bid: *bid, Decision: decision}); err != nil {
+			return nil, err
+		}
+
+		if decision == models.BidDecisionRejected {
+			s.logger.DebugContext(ctx, "Rejecting bid", "bid_id", bidID)
+			rejectedBid, err := repos.Bids.UpdateBidStatus(ctx, bidID, models.BidStatusRejected, bid.Version)
+			if err != nil {
+				s.logger.WarnContext(ctx, "Error rejecting bid", "error", err)
+				return nil, err
+			}
+			return rejectedBid, repos.Outbox.Append(ctx, event.BidStatusChanged{Meta: meta, Bid: *rejectedBid, PreviousStatus: bid.Status})
+		}
+
+		approved, _, err := repos.Bids.CountBidDecisions(ctx, bidID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Error counting decisions", "error", err)
+			return nil, err
+		}
+
+		responsibles, err := repos.Users.CountOrganizationResponsibles(ctx, tender.OrganizationID)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Error counting responsibles", "error", err)
+			return nil, err
+		}
+
+		quorum := models.BidDecisionQuorum
+		if responsibles < quorum {
+			quorum = responsibles
+		}
+
+		s.logger.DebugContext(ctx, "Counted bid approvals", "bid_id", bidID, "approved", approved, "quorum", quorum)
+		if approved < quorum {
+			return bid, nil
+		}
+
+		s.logger.DebugContext(ctx, "Approving bid", "bid_id", bidID)
+		approvedBid, err := repos.Bids.UpdateBidStatus(ctx, bidID, models.BidStatusApproved, bid.Version)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Error approving bid", "error", err)
+			return nil, err
+		}
+
+		s.logger.DebugContext(ctx, "Closing tender", "tender_id", tender.ID)
+		tender.Status = models.Closed
+		closedTender, err := repos.Tenders.UpdateTenderStatus(ctx, tender)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Error closing tender", "error", err)
+			return nil, err
+		}
+
+		return approvedBid, repos.Outbox.Append(ctx,
+			event.BidStatusChanged{Meta: meta, Bid: *approvedBid, PreviousStatus: bid.Status},
+			event.TenderClosed{Meta: meta, Tender: closedTender},
+		)
+	})
+}
+
+func (s *bidService) RollbackBid(ctx context.Context, bidID string, version int, expectedVersion int) (*models.Bid, error) {
+	_, err := uuid.Parse(bidID)
+	if err != nil || version <= 0 {
+		s.logger.InfoContext(ctx, "Invalid bid ID or version", "bid_id", bidID, "version", version)
+		return nil, my_errors.ErrBadRequest
+	}
+
+	s.logger.DebugContext(ctx, "Fetching bid", "bid_id", bidID)
+	bid, err := s.repo.GetBidByID(ctx, bidID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error fetching bid", "bid_id", bidID, "error", err)
+		return nil, err
+	}
+
+	user, err := s.caller(ctx)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Checking access rights", "username", user.Username, "bid_id", bidID)
+	isAuthor, err := s.isBidAuthor(ctx, bid, user.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return nil, err
+	}
+	if !isAuthor {
+		s.logger.InfoContext(ctx, "Access denied", "username", user.Username, "bid_id", bidID)
+		return nil, my_errors.ErrForbidden
+	}
+
+	if !bid.Status.IsEditable() {
+		s.logger.InfoContext(ctx, "Bid cannot be edited", "bid_id", bidID, "status", bid.Status)
+		return nil, my_errors.ErrBidNotEditable
+	}
+
+	if err := checkExpectedVersion(expectedVersion, bid.Version); err != nil {
+		s.logger.InfoContext(ctx, "Version conflict", "bid_id", bidID, "expected_version", expectedVersion, "version", bid.Version)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Fetching bid history", "bid_id", bidID, "version", version)
+	history, err := s.repo.GetBidHistoryByVersion(ctx, bidID, version)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error fetching bid history", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Rolling back bid", "bid_id", bidID, "version", version)
+	updatedBid, err := s.repo.EditBid(ctx, bidID, map[string]interface{}{
+		"description": history.Description,
+	}, bid.Version)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error updating bid", "error", err)
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "Bid rolled back", "bid_id", bidID, "to_version", version, "version", updatedBid.Version)
+	return updatedBid, nil
+}
+
+func (s *bidService) GetBidReviews(ctx context.Context, tenderID, authorUsername string, options repository.QueryOptions) ([]models.BidReview, error) {
+	_, err := uuid.Parse(tenderID)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Invalid tender ID", "tender_id", tenderID)
+		return nil, my_errors.ErrBadRequest
+	}
+
+	requester, err := s.caller(ctx)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Requester not resolved", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Fetching review author", "author_username", authorUsername)
+	author, err := s.userRepo.GetUserByUsername(ctx, authorUsername)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrUserNotFound) {
+			s.logger.InfoContext(ctx, "Review author not found", "author_username", authorUsername)
+			return nil, my_errors.ErrUserNotFound
+		}
+		s.logger.WarnContext(ctx, "Error fetching review author", "author_username", authorUsername, "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Fetching tender", "tender_id", tenderID)
+	tender, err := s.tenderRepo.GetTenderByID(ctx, tenderID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error fetching tender", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Checking access rights", "username", requester.Username, "organization_id", tender.OrganizationID)
+	hasPermission, err := s.userRepo.CheckUserPermission(ctx, requester.ID, tender.OrganizationID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error checking user permission", "error", err)
+		return nil, err
+	}
+	if !hasPermission {
+		s.logger.InfoContext(ctx, "Access denied", "username", requester.Username, "tender_id", tenderID)
+		return nil, my_errors.ErrForbidden
+	}
+
+	hasBid, err := s.repo.HasUserBidForTender(ctx, author.ID, tenderID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error checking review author bids", "error", err)
+		return nil, err
+	}
+	if !hasBid {
+		s.logger.DebugContext(ctx, "Review author has no bids for tender", "author_username", authorUsername, "tender_id", tenderID)
+		return nil, my_errors.ErrForbidden
+	}
+
+	s.logger.DebugContext(ctx, "Fetching reviews", "author_username", authorUsername, "limit", options.Limit, "offset", options.Offset)
+	reviews, err := s.repo.GetBidReviewsByAuthorID(ctx, author.ID, options)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Error fetching reviews", "error", err)
+		return nil, err
+	}
+
+	s.logger.DebugContext(ctx, "Retrieved reviews", "count", len(reviews), "author_username", authorUsername)
+	return reviews, nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"tender-service/internal/auth"
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/logging"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+	"tender-service/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixture runs the services over in-memory storage with one organization
+// owning tenders and one bidding on them.
+type fixture struct {
+	store   *memory.Store
+	tenders repository.TenderRepository
+	bids    repository.BidRepository
+	users   repository.UserRepository
+
+	bidService    BidService
+	tenderService TenderService
+
+	organizationID string
+	responsibles   []models.User
+	bidder         models.User
+}
+
+// newFixture gives the tender organization the given number of responsibles.
+func newFixture(t *testing.T, responsibles int) fixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := fixture{
+		store:   store,
+		tenders: memory.NewTenderRepository(store),
+		bids:    memory.NewBidRepository(store),
+		users:   memory.NewUserRepository(store),
+	}
+	f.organizationID = store.AddOrganization(models.Organization{Name: "Buyer", Type: models.OrganizationLLC}).ID
+	for i := 0; i < responsibles; i++ {
+		responsible := store.AddEmployee(models.User{Username: "responsible" + string(rune('a'+i))})
+		store.AddOrganizationResponsible(f.organizationID, responsible.ID)
+		f.responsibles = append(f.responsibles, responsible)
+	}
+	bidderOrganization := store.AddOrganization(models.Organization{Name: "Seller", Type: models.OrganizationLLC})
+	f.bidder = store.AddEmployee(models.User{Username: "bidder"})
+	store.AddOrganizationResponsible(bidderOrganization.ID, f.bidder.ID)
+
+	transitions := models.NewTenderStateMachine()
+	transitions.AddGuard(models.Created, models.Published, models.RequireTenderDescription)
+	transitions.AddGuard(models.Published, models.Closed, NoPendingBids)
+
+	uow := memory.NewUnitOfWork(store)
+	userService := NewUserService(f.users, memory.NewOrganizationRepository(store), uow, nil, logging.Discard())
+	f.tenderService = NewTenderService(f.tenders, userService, transitions, uow, logging.Discard())
+	f.bidService = NewBidService(f.bids, f.tenders, f.users, uow, logging.Discard())
+	return f
+}
+
+// as returns a context in which user makes the request.
+func (f fixture) as(user models.User) context.Context {
+	return auth.WithIdentity(context.Background(), auth.Identity{UserID: user.ID, Authenticated: true})
+}
+
+func (f fixture) tender(t *testing.T, status models.TenderStatus, description string) models.Tender {
+	t.Helper()
+	tender, err := f.tenders.CreateTender(context.Background(), models.Tender{
+		Name:           "Delivery",
+		Description:    description,
+		Status:         status,
+		OrganizationID: f.organizationID,
+		CreatorID:      f.responsibles[0].ID,
+	})
+	require.NoError(t, err)
+	return tender
+}
+
+func (f fixture) bid(t *testing.T, tender models.Tender, status models.BidStatus) models.Bid {
+	t.Helper()
+	bid, err := f.bids.CreateBid(context.Background(), &models.Bid{
+		Description: "We can deliver",
+		TenderID:    tender.ID,
+		UserID:      f.bidder.ID,
+		AuthorType:  models.BidAuthorTypeUser,
+		Status:      status,
+	})
+	require.NoError(t, err)
+	return *bid
+}
+
+func TestSubmitBidDecision_Quorum(t *testing.T) {
+	approve, reject := models.BidDecisionApproved, models.BidDecisionRejected
+
+	for _, tc := range []struct {
+		name         string
+		responsibles int
+		// inactive responsibles are deactivated before the decisions.
+		inactive   int
+		decisions  []models.BidDecision
+		wantBid    models.BidStatus
+		wantTender models.TenderStatus
+	}{
+		{"a sole responsible approves alone", 1, 0, []models.BidDecision{approve}, models.BidStatusApproved, models.Closed},
+		{"two responsibles both approve", 2, 0, []models.BidDecision{approve, approve}, models.BidStatusApproved, models.Closed},
+		{"one of two approvals is not enough", 2, 0, []models.BidDecision{approve}, models.BidStatusPublished, models.Published},
+		{"two of five approvals are not enough", 5, 0, []models.BidDecision{approve, approve}, models.BidStatusPublished, models.Published},
+		{"three approvals suffice for five", 5, 0, []models.BidDecision{approve, approve, approve}, models.BidStatusApproved, models.Closed},
+		{"inactive responsibles do not count", 3, 1, []models.BidDecision{approve, approve}, models.BidStatusApproved, models.Closed},
+		{"a single rejection rejects", 3, 0, []models.BidDecision{reject}, models.BidStatusRejected, models.Published},
+		{"a rejection wins over approvals", 3, 0, []models.BidDecision{approve, approve, reject}, models.BidStatusRejected, models.Published},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t, tc.responsibles)
+			for _, responsible := range f.responsibles[tc.responsibles-tc.inactive:] {
+				require.NoError(t, f.users.DeactivateUser(context.Background(), responsible.ID))
+			}
+			tender := f.tender(t, models.Published, "Deliver equipment")
+			bid := f.bid(t, tender, models.BidStatusPublished)
+
+			var decided *models.Bid
+			for i, decision := range tc.decisions {
+				var err error
+				decided, err = f.bidService.SubmitBidDecision(f.as(f.responsibles[i]), bid.ID, decision)
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantBid, decided.Status)
+
+			stored, err := f.tenders.GetTenderByID(context.Background(), tender.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTender, stored.Status)
+		})
+	}
+}
+
+func TestSubmitBidDecision_Refusals(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// setup returns the bid to decide and who decides it.
+		setup   func(t *testing.T, f fixture) (models.Bid, models.User)
+		wantErr error
+	}{
+		{"outsiders may not decide", func(t *testing.T, f fixture) (models.Bid, models.User) {
+			return f.bid(t, f.tender(t, models.Published, "Deliver"), models.BidStatusPublished), f.bidder
+		}, my_errors.ErrForbidden},
+		{"unpublished bids cannot be decided", func(t *testing.T, f fixture) (models.Bid, models.User) {
+			return f.bid(t, f.tender(t, models.Published, "Deliver"), models.BidStatusCreated), f.responsibles[0]
+		}, my_errors.ErrBidDecisionNotAllowed},
+		{"rejected bids cannot be decided again", func(t *testing.T, f fixture) (models.Bid, models.User) {
+			return f.bid(t, f.tender(t, models.Published, "Deliver"), models.BidStatusRejected), f.responsibles[0]
+		}, my_errors.ErrBidDecisionNotAllowed},
+		{"closed tenders take no decisions", func(t *testing.T, f fixture) (models.Bid, models.User) {
+			return f.bid(t, f.tender(t, models.Closed, "Deliver"), models.BidStatusPublished), f.responsibles[0]
+		}, my_errors.ErrTenderAlreadyDecided},
+		{"only one bid per tender is approved", func(t *testing.T, f fixture) (models.Bid, models.User) {
+			tender := f.tender(t, models.Published, "Deliver")
+			f.bid(t, tender, models.BidStatusApproved)
+			return f.bid(t, tender, models.BidStatusPublished), f.responsibles[0]
+		}, my_errors.ErrTenderAlreadyDecided},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t, 1)
+			bid, caller := tc.setup(t, f)
+
+			_, err := f.bidService.SubmitBidDecision(f.as(caller), bid.ID, models.BidDecisionApproved)
+			assert.ErrorIs(t, err, tc.wantErr)
+
+			stored, err := f.bids.GetBidByID(context.Background(), bid.ID)
+			require.NoError(t, err)
+			assert.Equal(t, bid.Status, stored.Status)
+		})
+	}
+}
+
+func TestUpdateBidStatus_Transitions(t *testing.T) {
+	for _, tc := range []struct {
+		from    models.BidStatus
+		to      string
+		wantErr error
+	}{
+		{models.BidStatusCreated, "PUBLISHED", nil},
+		{models.BidStatusCreated, "CANCELED", nil},
+		{models.BidStatusPublished, "CANCELED", nil},
+		{models.BidStatusPublished, "CREATED", my_errors.ErrInvalidBidTransition},
+		{models.BidStatusCanceled, "PUBLISHED", my_errors.ErrInvalidBidTransition},
+		{models.BidStatusApproved, "CANCELED", my_errors.ErrInvalidBidTransition},
+		{models.BidStatusRejected, "PUBLISHED", my_errors.ErrInvalidBidTransition},
+		// Decision outcomes are reached only through decisions.
+		{models.BidStatusPublished, "APPROVED", my_errors.ErrInvalidBidTransition},
+		{models.BidStatusPublished, "REJECTED", my_errors.ErrInvalidBidTransition},
+		{models.BidStatusCreated, "OPEN", my_errors.ErrInvalidBidStatus},
+	} {
+		t.Run(string(tc.from)+" to "+tc.to, func(t *testing.T) {
+			f := newFixture(t, 1)
+			bid := f.bid(t, f.tender(t, models.Published, "Deliver"), tc.from)
+
+			updated, err := f.bidService.UpdateBidStatus(f.as(f.bidder), bid.ID, tc.to, 0)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.BidStatus(tc.to), updated.Status)
+			assert.Equal(t, bid.Version+1, updated.Version)
+		})
+	}
+}
+
+func TestUpdateBidStatus_OnlyAuthors(t *testing.T) {
+	f := newFixture(t, 1)
+	bid := f.bid(t, f.tender(t, models.Published, "Deliver"), models.BidStatusCreated)
+
+	_, err := f.bidService.UpdateBidStatus(f.as(f.responsibles[0]), bid.ID, "PUBLISHED", 0)
+	assert.ErrorIs(t, err, my_errors.ErrForbidden)
+	_, err = f.bidService.UpdateBidStatus(f.as(f.bidder), bid.ID, "PUBLISHED", bid.Version+1)
+	assert.ErrorIs(t, err, my_errors.ErrVersionConflict)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"tender-service/internal/auth"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	my_errors "tender-service/internal/errors"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, name, description, organizationType string) (models.Organization, error)
+	GetOrganizations(ctx context.Context) ([]models.Organization, error)
+	GetOrganization(ctx context.Context, organizationID string) (models.Organization, error)
+	GetUserOrganizations(ctx context.Context) ([]models.Organization, error)
+	GetResponsibles(ctx context.Context, organizationID string) ([]models.User, error)
+	AddResponsible(ctx context.Context, organizationID, username string) (models.User, error)
+	RemoveResponsible(ctx context.Context, organizationID, username string) error
+}
+
+type organizationService struct {
+	repo     repository.OrganizationRepository
+	userRepo repository.UserRepository
+	logger   *slog.Logger
+}
+
+func NewOrganizationService(repo repository.OrganizationRepository, userRepo repository.UserRepository, logger *slog.Logger) OrganizationService {
+	return &organizationService{repo: repo, userRepo: userRepo, logger: logger}
+}
+
+// caller returns the employee making the request. Every organization
+// endpoint requires one.
+func (s *organizationService) caller(ctx context.Context) (*models.User, error) {
+	user, err := auth.Caller(ctx, s.userRepo)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrUserNotFound) {
+			return nil, my_errors.ErrUnauthorized
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, name, description, organizationType string) (models.Organization, error) {
+	user, err := s.caller(ctx)
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 100 {
+		return models.Organization{}, my_errors.ErrBadRequest
+	}
+	parsedType, err := models.ParseOrganizationType(organizationType)
+	if err != nil {
+		return models.Organization{}, my_errors.ErrInvalidOrganizationType
+	}
+
+	organization, err := s.repo.CreateOrganization(ctx, models.Organization{
+		Name:        name,
+		Description: description,
+		Type:        parsedType,
+	}, user.ID)
+	if err != nil {
+		return models.Organization{}, err
+	}
+
+	s.logger.InfoContext(ctx, "Organization created", "organization_id", organization.ID, "actor", user.Username)
+	return organization, nil
+}
+
+func (s *organizationService) GetOrganizations(ctx context.Context) ([]models.Organization, error) {
+	if _, err := s.caller(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.GetOrganizations(ctx)
+}
+
+func (s *organizationService) GetOrganization(ctx context.Context, organizationID string) (models.Organization, error) {
+	if _, err := s.caller(ctx); err != nil {
+		return models.Organization{}, err
+	}
+	if _, err := uuid.Parse(organizationID); err != nil {
+		return models.Organization{}, my_errors.ErrBadRequest
+	}
+	return s.repo.GetOrganizationByID(ctx, organizationID)
+}
+
+func (s *organizationService) GetUserOrganizations(ctx context.Context) ([]models.Organization, error) {
+	user, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserOrganizations(ctx, user.ID)
+}
+
+func (s *organizationService) GetResponsibles(ctx context.Context, organizationID string) ([]models.User, error) {
+	if _, err := s.GetOrganization(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetResponsibles(ctx, organizationID)
+}
+
+// AddResponsible lets a responsible of the organization bring in another
+// employee.
+func (s *organizationService) AddResponsible(ctx context.Context, organizationID, username string) (models.User, error) {
+	user, err := s.authorizeMembershipChange(ctx, organizationID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	member, err := s.userRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return models.User{}, err
+	}
+	if err := s.repo.AddResponsible(ctx, organizationID, member.ID); err != nil {
+		return models.User{}, err
+	}
+
+	s.logger.InfoContext(ctx, "Responsible added", "organization_id", organizationID, "username", member.Username, "actor", user.Username)
+	return *member, nil
+}
+
+// RemoveResponsible lets a responsible of the organization remove any
+// responsible, themselves included, as long as one remains.
+func (s *organizationService) RemoveResponsible(ctx context.Context, organizationID, username string) error {
+	user, err := s.authorizeMembershipChange(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.userRepo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.RemoveResponsible(ctx, organizationID, member.ID); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Responsible removed", "organization_id", organizationID, "username", member.Username, "actor", user.Username)
+	return nil
+}
+
+func (s *organizationService) authorizeMembershipChange(ctx context.Context, organizationID string) (*models.User, error) {
+	user, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(organizationID); err != nil {
+		return nil, my_errors.ErrBadRequest
+	}
+	if _, err := s.repo.GetOrganizationByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	responsible, err := s.repo.IsResponsible(ctx, user.ID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !responsible {
+		return nil, my_errors.ErrForbidden
+	}
+	return user, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"tender-service/internal/auth"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+
+	my_errors "tender-service/internal/errors"
+)
+
+type OutboxService interface {
+	ListOutbox(ctx context.Context, statuses []string, limit, offset int) ([]models.OutboxEntry, error)
+}
+
+type outboxService struct {
+	repo     repository.OutboxRepository
+	userRepo repository.UserRepository
+	admins   adminSet
+}
+
+// NewOutboxService builds the service. Only the employees named in
+// adminUsernames, authenticated by a token, may use it.
+func NewOutboxService(repo repository.OutboxRepository, userRepo repository.UserRepository, adminUsernames []string) OutboxService {
+	return &outboxService{repo: repo, userRepo: userRepo, admins: newAdminSet(adminUsernames)}
+}
+
+func (s *outboxService) authorizeAdmin(ctx context.Context) error {
+	user, err := auth.Caller(ctx, s.userRepo)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrUserNotFound) {
+			return my_errors.ErrUnauthorized
+		}
+		return err
+	}
+	if !s.admins.allows(ctx, user) {
+		return my_errors.ErrForbidden
+	}
+	return nil
+}
+
+// ListOutbox lists outbox entries in the given statuses, oldest first, so
+// that stuck and failed deliveries can be inspected.
+func (s *outboxService) ListOutbox(ctx context.Context, statuses []string, limit, offset int) ([]models.OutboxEntry, error) {
+	if err := s.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	parsed := make([]models.OutboxStatus, 0, len(statuses))
+	for _, status := range statuses {
+		outboxStatus, err := models.ParseOutboxStatus(status)
+		if err != nil {
+			return nil, my_errors.ErrBadRequest
+		}
+		parsed = append(parsed, outboxStatus)
+	}
+
+	return s.repo.ListOutbox(ctx, parsed, limit, offset)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"strings"
+
+	my_errors "tender-service/internal/errors"
+)
+
+// validateStatuses rejects status filters that parse does not accept, so a
+// typo is reported instead of silently matching nothing.
+func validateStatuses[S any](statuses []string, parse func(string) (S, error)) error {
+	for _, status := range statuses {
+		if _, err := parse(status); err != nil {
+			return my_errors.ErrBadRequest
+		}
+	}
+	return nil
+}
+
+// validateSearchQuery rejects blank and overly long search queries.
+func validateSearchQuery(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" || len(query) > 200 {
+		return my_errors.ErrBadRequest
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+)
+
+// TenderPolicy decides which tenders an employee may see and manage. An empty
+// userID stands for an unauthenticated caller.
+type TenderPolicy interface {
+	ListFilter(ctx context.Context, userID string) (repository.TenderFilter, error)
+	OrganizationFilter(ctx context.Context, userID string) (repository.TenderFilter, error)
+	CanView(ctx context.Context, tender models.Tender, userID string) (bool, error)
+	CanManage(ctx context.Context, tender models.Tender, userID string) (bool, error)
+}
+
+type tenderPolicy struct {
+	repo repository.TenderRepository
+}
+
+func NewTenderPolicy(repo repository.TenderRepository) TenderPolicy {
+	return &tenderPolicy{repo: repo}
+}
+
+// publicTenderStatuses are visible to everyone, including unauthenticated callers.
+var publicTenderStatuses = []models.TenderStatus{models.Published}
+
+func (p *tenderPolicy) ListFilter(ctx context.Context, userID string) (repository.TenderFilter, error) {
+	filter := repository.TenderFilter{PublicStatuses: publicTenderStatuses}
+	if userID == "" {
+		return filter, nil
+	}
+
+	organizationIDs, err := p.repo.GetUserOrganizationIDs(ctx, userID)
+	if err != nil {
+		return repository.TenderFilter{}, err
+	}
+	filter.OrganizationIDs = organizationIDs
+	return filter, nil
+}
+
+func (p *tenderPolicy) OrganizationFilter(ctx context.Context, userID string) (repository.TenderFilter, error) {
+	organizationIDs, err := p.repo.GetUserOrganizationIDs(ctx, userID)
+	if err != nil {
+		return repository.TenderFilter{}, err
+	}
+	return repository.TenderFilter{OrganizationIDs: organizationIDs}, nil
+}
+
+func (p *tenderPolicy) CanView(ctx context.Context, tender models.Tender, userID string) (bool, error) {
+	for _, status := range publicTenderStatuses {
+		if tender.Status == status {
+			return true, nil
+		}
+	}
+	return p.CanManage(ctx, tender, userID)
+}
+
+func (p *tenderPolicy) CanManage(ctx context.Context, tender models.Tender, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	if tender.CreatorID == userID {
+		return true, nil
+	}
+	return p.repo.IsUserResponsibleForOrganization(ctx, userID, tender.OrganizationID)
+}
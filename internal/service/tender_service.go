@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
-	"log"
+	"log/slog"
+	"tender-service/internal/auth"
+	"tender-service/internal/event"
 	"tender-service/internal/models"
 	"tender-service/internal/repository"
+	"time"
 
 	my_errors "tender-service/internal/errors"
 
@@ -12,148 +16,316 @@ import (
 )
 
 type TenderService interface {
-	GetTenders(serviceType string) ([]models.Tender, error)
-	CreateTender(tender models.Tender, creatorUsername string) (models.Tender, error)
-	GetUserTenders(username string) ([]models.Tender, error)
-	GetTenderStatus(tenderId, username string) (models.TenderStatus, error)
-	UpdateTenderStatus(tenderId string, status models.TenderStatus, username string) error
-	EditTender(tenderId, username string, name, description, serviceType *string) error
-	RollbackTenderVersion(tenderId string, version int, username string) error
+	GetTenders(ctx context.Context, options repository.QueryOptions) ([]models.Tender, error)
+	SearchTenders(ctx context.Context, query string, options repository.QueryOptions) ([]models.TenderSearchResult, error)
+	CreateTender(ctx context.Context, tender models.Tender) (models.Tender, error)
+	GetUserTenders(ctx context.Context, options repository.QueryOptions) ([]models.Tender, error)
+	// GetTenderStatus returns the status of the tender and its current version.
+	GetTenderStatus(ctx context.Context, tenderId string) (models.TenderStatus, int, error)
+	UpdateTenderStatus(ctx context.Context, tenderId string, status models.TenderStatus, expectedVersion int) (models.Tender, error)
+	EditTender(ctx context.Context, tenderId string, expectedVersion int, name, description, serviceType *string, submissionDeadline, decisionDeadline *time.Time) (models.Tender, error)
+	RollbackTenderVersion(ctx context.Context, tenderId string, version int, expectedVersion int) (models.Tender, error)
 }
 
 type tenderService struct {
 	repo        repository.TenderRepository
 	userService UserService
+	policy      TenderPolicy
+	transitions *models.TenderStateMachine
+	uow         repository.UnitOfWork
+	logger      *slog.Logger
 }
 
-func NewTenderService(repo repository.TenderRepository, userService UserService) TenderService {
-	return &tenderService{repo: repo, userService: userService}
+// NewTenderService builds the service. Changes run in units of work so that
+// the events describing them are stored in the outbox atomically.
+func NewTenderService(repo repository.TenderRepository, userService UserService, transitions *models.TenderStateMachine, uow repository.UnitOfWork, logger *slog.Logger) TenderService {
+	return &tenderService{repo: repo, userService: userService, policy: NewTenderPolicy(repo), transitions: transitions, uow: uow, logger: logger}
 }
 
-func (s *tenderService) GetTenders(serviceType string) ([]models.Tender, error) {
-	return s.repo.GetTenders(serviceType)
+// NoPendingBids blocks closing a tender while published bids still wait for a
+// decision.
+func NoPendingBids(ctx context.Context, bids models.BidCounter, tender models.Tender) error {
+	pending, err := bids.CountTenderBids(ctx, tender.ID, []models.BidStatus{models.BidStatusPublished})
+	if err != nil {
+		return err
+	}
+	if pending > 0 {
+		return models.RejectTransition("cannot close a tender with %d bids pending decision", pending)
+	}
+	return nil
 }
 
-func (s *tenderService) CreateTender(tender models.Tender, creatorUsername string) (models.Tender, error) {
-
-	creatorID, err := s.userService.GetUserIDByUsername(creatorUsername)
+// callerID returns the ID of the employee making the request.
+func (s *tenderService) callerID(ctx context.Context) (string, error) {
+	user, err := auth.Caller(ctx, s.userService)
 	if err != nil {
 		if errors.Is(err, my_errors.ErrUserNotFound) {
-			return models.Tender{}, my_errors.ErrUnauthorized
+			return "", my_errors.ErrUnauthorized
 		}
+		return "", err
+	}
+	return user.ID, nil
+}
+
+// optionalCallerID is callerID for endpoints that anonymous requests may use
+// too; it returns an empty ID for them.
+func (s *tenderService) optionalCallerID(ctx context.Context) (string, error) {
+	if auth.IdentityFromContext(ctx).Anonymous() {
+		return "", nil
+	}
+	return s.callerID(ctx)
+}
+
+func (s *tenderService) GetTenders(ctx context.Context, options repository.QueryOptions) ([]models.Tender, error) {
+	if err := validateStatuses(options.Statuses, models.ParseTenderStatus); err != nil {
+		return nil, err
+	}
+
+	userId, err := s.optionalCallerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.policy.ListFilter(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTenders(ctx, filter, options)
+}
+
+// SearchTenders finds the tenders matching query among those GetTenders would
+// list, best matches first.
+func (s *tenderService) SearchTenders(ctx context.Context, query string, options repository.QueryOptions) ([]models.TenderSearchResult, error) {
+	if err := validateSearchQuery(query); err != nil {
+		return nil, err
+	}
+	if err := validateStatuses(options.Statuses, models.ParseTenderStatus); err != nil {
+		return nil, err
+	}
+
+	userId, err := s.optionalCallerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.policy.ListFilter(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.SearchTenders(ctx, query, filter, options)
+}
+
+func (s *tenderService) CreateTender(ctx context.Context, tender models.Tender) (models.Tender, error) {
+
+	creatorID, err := s.callerID(ctx)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
 		return models.Tender{}, err
 	}
 
 	tender.CreatorID = creatorID
+	tender.Status = models.Created
+
+	if !tender.HasValidDeadlines() {
+		s.logger.InfoContext(ctx, "Invalid tender deadlines", "submission_deadline", tender.SubmissionDeadline, "decision_deadline", tender.DecisionDeadline)
+		return models.Tender{}, my_errors.ErrBadRequest
+	}
 
-	createdTender, err := s.repo.CreateTender(tender)
+	created, err := inTransaction(ctx, s.uow, func(repos repository.Repositories) (models.Tender, error) {
+		createdTender, err := repos.Tenders.CreateTender(ctx, tender)
+		if err != nil {
+			return models.Tender{}, err
+		}
+		return createdTender, repos.Outbox.Append(ctx, event.TenderCreated{Meta: event.NewMeta(creatorID), Tender: createdTender})
+	})
 	if err != nil {
+		s.logger.WarnContext(ctx, "Error creating tender", "error", err)
 		return models.Tender{}, err
 	}
 
-	return createdTender, nil
+	s.logger.InfoContext(ctx, "Tender created", "tender_id", created.ID, "organization_id", created.OrganizationID)
+	return created, nil
 }
 
-func (s *tenderService) GetUserTenders(username string) ([]models.Tender, error) {
-	return s.repo.GetUserTenders(username)
+func (s *tenderService) GetUserTenders(ctx context.Context, options repository.QueryOptions) ([]models.Tender, error) {
+	if err := validateStatuses(options.Statuses, models.ParseTenderStatus); err != nil {
+		return nil, err
+	}
+
+	userId, err := s.callerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := s.policy.OrganizationFilter(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetTenders(ctx, filter, options)
 }
 
-func (s *tenderService) GetTenderStatus(tenderId, username string) (models.TenderStatus, error) {
+func (s *tenderService) GetTenderStatus(ctx context.Context, tenderId string) (models.TenderStatus, int, error) {
 
 	_, err := uuid.Parse(tenderId)
 	if err != nil {
-		return "", my_errors.ErrBadRequest
+		return "", 0, my_errors.ErrBadRequest
 	}
 
-	tender, err := s.repo.GetTenderByID(tenderId)
+	tender, err := s.repo.GetTenderByID(ctx, tenderId)
 	if err != nil {
 		if errors.Is(err, my_errors.ErrTenderNotFound) {
-			return "", my_errors.ErrTenderNotFound
+			return "", 0, my_errors.ErrTenderNotFound
 		}
-		return "", err
+		return "", 0, err
 	}
 
-	userId, err := s.userService.GetUserIDByUsername(username)
+	userId, err := s.optionalCallerID(ctx)
 	if err != nil {
-		return "", my_errors.ErrUnauthorized
+		return "", 0, err
 	}
 
-	isResponsible, err := s.repo.IsUserResponsibleForOrganization(userId, tender.OrganizationID)
+	canView, err := s.policy.CanView(ctx, tender, userId)
 	if err != nil {
-		return "", err
+		return "", 0, err
 	}
 
-	if tender.CreatorID != userId && !isResponsible {
-		return "", my_errors.ErrForbidden
+	if !canView {
+		return "", 0, my_errors.ErrForbidden
 	}
 
-	return tender.Status, nil
+	return tender.Status, tender.Version, nil
 }
 
-func (s *tenderService) UpdateTenderStatus(tenderId string, status models.TenderStatus, username string) error {
+func (s *tenderService) UpdateTenderStatus(ctx context.Context, tenderId string, status models.TenderStatus, expectedVersion int) (models.Tender, error) {
 
 	_, err := uuid.Parse(tenderId)
 	if err != nil {
-		return my_errors.ErrBadRequest
+		s.logger.InfoContext(ctx, "Invalid tender ID", "tender_id", tenderId)
+		return models.Tender{}, my_errors.ErrBadRequest
 	}
 
-	tender, err := s.repo.GetTenderByID(tenderId)
+	tender, err := s.repo.GetTenderByID(ctx, tenderId)
 	if err != nil {
 		if errors.Is(err, my_errors.ErrTenderNotFound) {
-			return my_errors.ErrTenderNotFound
+			s.logger.InfoContext(ctx, "Tender not found", "tender_id", tenderId)
+			return models.Tender{}, my_errors.ErrTenderNotFound
 		}
-		return err
+		s.logger.WarnContext(ctx, "Error fetching tender", "tender_id", tenderId, "error", err)
+		return models.Tender{}, err
 	}
 
-	userId, err := s.userService.GetUserIDByUsername(username)
+	userId, err := s.callerID(ctx)
 	if err != nil {
-		return my_errors.ErrUnauthorized
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return models.Tender{}, err
 	}
 
-	isResponsible, err := s.repo.IsUserResponsibleForOrganization(userId, tender.OrganizationID)
+	canManage, err := s.policy.CanManage(ctx, tender, userId)
 	if err != nil {
-		return err
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return models.Tender{}, err
 	}
 
-	if tender.CreatorID != userId && !isResponsible {
-		return my_errors.ErrForbidden
+	if !canManage {
+		s.logger.InfoContext(ctx, "Access denied", "user_id", userId, "tender_id", tenderId)
+		return models.Tender{}, my_errors.ErrForbidden
 	}
 
-	tender.Status = status
-	err = s.repo.UpdateTenderStatus(tender)
+	if err := checkExpectedVersion(expectedVersion, tender.Version); err != nil {
+		s.logger.InfoContext(ctx, "Version conflict", "tender_id", tenderId, "expected_version", expectedVersion, "version", tender.Version)
+		return models.Tender{}, err
+	}
+
+	previous := tender.Status
+	updated, err := inTransaction(ctx, s.uow, func(repos repository.Repositories) (models.Tender, error) {
+		// Guards must see the same bids as the update, so the status and
+		// version are checked again under the lock.
+		tender, err := repos.Tenders.GetTenderForUpdate(ctx, tenderId)
+		if err != nil {
+			return models.Tender{}, err
+		}
+		if err := checkExpectedVersion(expectedVersion, tender.Version); err != nil {
+			return models.Tender{}, err
+		}
+		if err := s.transitions.Transition(ctx, repos.Bids, tender, status); err != nil {
+			return models.Tender{}, err
+		}
+		previous = tender.Status
+
+		tender.Status = status
+		updated, err := repos.Tenders.UpdateTenderStatus(ctx, tender)
+		if err != nil {
+			return models.Tender{}, err
+		}
+
+		switch updated.Status {
+		case models.Published:
+			err = repos.Outbox.Append(ctx, event.TenderPublished{Meta: event.NewMeta(userId), Tender: updated})
+		case models.Closed:
+			err = repos.Outbox.Append(ctx, event.TenderClosed{Meta: event.NewMeta(userId), Tender: updated})
+		}
+		return updated, err
+	})
 	if err != nil {
-		return err
+		if errors.Is(err, my_errors.ErrInvalidTenderTransition) || errors.Is(err, my_errors.ErrVersionConflict) {
+			s.logger.InfoContext(ctx, "Tender status not changed", "tender_id", tenderId, "status", status, "error", err)
+		} else {
+			s.logger.WarnContext(ctx, "Error updating tender status", "tender_id", tenderId, "error", err)
+		}
+		return models.Tender{}, err
 	}
 
-	return nil
+	s.logger.InfoContext(ctx, "Tender status changed", "tender_id", tenderId, "from", previous, "to", updated.Status)
+	return updated, nil
 }
 
-func (s *tenderService) EditTender(tenderId, username string, name, description, serviceType *string) error {
+// EditTender changes the given fields. Deadlines can be set or moved but not
+// removed.
+func (s *tenderService) EditTender(ctx context.Context, tenderId string, expectedVersion int, name, description, serviceType *string, submissionDeadline, decisionDeadline *time.Time) (models.Tender, error) {
 	_, err := uuid.Parse(tenderId)
 	if err != nil {
-		return my_errors.ErrBadRequest
+		s.logger.InfoContext(ctx, "Invalid tender ID", "tender_id", tenderId)
+		return models.Tender{}, my_errors.ErrBadRequest
 	}
 
-	tender, err := s.repo.GetTenderByID(tenderId)
+	tender, err := s.repo.GetTenderByID(ctx, tenderId)
 	if err != nil {
 		if errors.Is(err, my_errors.ErrTenderNotFound) {
-			return my_errors.ErrTenderNotFound
+			s.logger.InfoContext(ctx, "Tender not found", "tender_id", tenderId)
+			return models.Tender{}, my_errors.ErrTenderNotFound
 		}
-		return err
+		s.logger.WarnContext(ctx, "Error fetching tender", "tender_id", tenderId, "error", err)
+		return models.Tender{}, err
 	}
 
-	userId, err := s.userService.GetUserIDByUsername(username)
+	userId, err := s.callerID(ctx)
 	if err != nil {
-		return my_errors.ErrUnauthorized
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return models.Tender{}, err
 	}
 
-	isResponsible, err := s.repo.IsUserResponsibleForOrganization(userId, tender.OrganizationID)
+	canManage, err := s.policy.CanManage(ctx, tender, userId)
 	if err != nil {
-		return err
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return models.Tender{}, err
+	}
+
+	if !canManage {
+		s.logger.InfoContext(ctx, "Access denied", "user_id", userId, "tender_id", tenderId)
+		return models.Tender{}, my_errors.ErrForbidden
 	}
 
-	if tender.CreatorID != userId && !isResponsible {
-		return my_errors.ErrForbidden
+	if !tender.Status.IsEditable() {
+		s.logger.InfoContext(ctx, "Tender cannot be edited", "tender_id", tenderId, "status", tender.Status)
+		return models.Tender{}, my_errors.ErrTenderNotEditable
+	}
+
+	if err := checkExpectedVersion(expectedVersion, tender.Version); err != nil {
+		s.logger.InfoContext(ctx, "Version conflict", "tender_id", tenderId, "expected_version", expectedVersion, "version", tender.Version)
+		return models.Tender{}, err
 	}
 
 	if name != nil {
@@ -165,77 +337,109 @@ func (s *tenderService) EditTender(tenderId, username string, name, description,
 	if serviceType != nil {
 		tender.ServiceType = *serviceType
 	}
+	if submissionDeadline != nil {
+		tender.SubmissionDeadline = submissionDeadline
+	}
+	if decisionDeadline != nil {
+		tender.DecisionDeadline = decisionDeadline
+	}
+	if !tender.HasValidDeadlines() {
+		s.logger.InfoContext(ctx, "Invalid tender deadlines", "tender_id", tenderId, "submission_deadline", tender.SubmissionDeadline, "decision_deadline", tender.DecisionDeadline)
+		return models.Tender{}, my_errors.ErrBadRequest
+	}
 
-	err = s.repo.UpdateTender(tender)
+	updated, err := inTransaction(ctx, s.uow, func(repos repository.Repositories) (models.Tender, error) {
+		updated, err := repos.Tenders.UpdateTender(ctx, tender)
+		if err != nil {
+			return models.Tender{}, err
+		}
+		return updated, repos.Outbox.Append(ctx, event.TenderEdited{Meta: event.NewMeta(userId), Tender: updated})
+	})
 	if err != nil {
-		return err
+		s.logger.WarnContext(ctx, "Error updating tender", "error", err)
+		return models.Tender{}, err
 	}
 
-	return nil
+	s.logger.InfoContext(ctx, "Tender edited", "tender_id", tenderId, "version", updated.Version)
+	return updated, nil
 }
 
-func (s *tenderService) RollbackTenderVersion(tenderId string, version int, username string) error {
-	log.Printf("RollbackTenderVersion: Parsing tender ID: %s", tenderId)
+func (s *tenderService) RollbackTenderVersion(ctx context.Context, tenderId string, version int, expectedVersion int) (models.Tender, error) {
 	_, err := uuid.Parse(tenderId)
 	if err != nil {
-		log.Printf("RollbackTenderVersion: Invalid tender ID format: %s", tenderId)
-		return my_errors.ErrBadRequest
+		s.logger.InfoContext(ctx, "Invalid tender ID", "tender_id", tenderId)
+		return models.Tender{}, my_errors.ErrBadRequest
 	}
 
-	log.Printf("RollbackTenderVersion: Fetching tender by ID: %s", tenderId)
-	tender, err := s.repo.GetTenderByID(tenderId)
+	userId, err := s.callerID(ctx)
 	if err != nil {
-		if errors.Is(err, my_errors.ErrTenderNotFound) {
-			log.Printf("RollbackTenderVersion: Tender not found: %s", tenderId)
-			return my_errors.ErrTenderNotFound
-		}
-		log.Printf("RollbackTenderVersion: Error fetching tender: %v", err)
-		return err
+		s.logger.InfoContext(ctx, "Caller not resolved", "error", err)
+		return models.Tender{}, err
 	}
 
-	log.Printf("RollbackTenderVersion: Fetching tender history for version: %d, tenderId: %s", version, tenderId)
-	history, err := s.repo.GetTenderHistoryByVersion(tenderId, version)
+	s.logger.DebugContext(ctx, "Fetching tender", "tender_id", tenderId)
+	tender, err := s.repo.GetTenderByID(ctx, tenderId)
 	if err != nil {
-		if errors.Is(err, my_errors.ErrTenderHistoryNotFound) {
-			log.Printf("RollbackTenderVersion: Tender history not found for version: %d, tenderId: %s", version, tenderId)
-			return my_errors.ErrTenderHistoryNotFound
+		if errors.Is(err, my_errors.ErrTenderNotFound) {
+			s.logger.InfoContext(ctx, "Tender not found", "tender_id", tenderId)
+			return models.Tender{}, my_errors.ErrTenderNotFound
 		}
-		log.Printf("RollbackTenderVersion: Error fetching tender history: %v", err)
-		return err
+		s.logger.WarnContext(ctx, "Error fetching tender", "tender_id", tenderId, "error", err)
+		return models.Tender{}, err
 	}
 
-	log.Printf("RollbackTenderVersion: Fetching user ID by username: %s", username)
-	userId, err := s.userService.GetUserIDByUsername(username)
+	s.logger.DebugContext(ctx, "Checking access rights", "user_id", userId, "tender_id", tenderId)
+	canManage, err := s.policy.CanManage(ctx, tender, userId)
 	if err != nil {
-		log.Printf("RollbackTenderVersion: Unauthorized user: %s", username)
-		return my_errors.ErrUnauthorized
+		s.logger.WarnContext(ctx, "Error checking access rights", "error", err)
+		return models.Tender{}, err
 	}
 
-	log.Printf("RollbackTenderVersion: Checking if user ID: %s is responsible for organization: %s", userId, tender.OrganizationID)
-	isResponsible, err := s.repo.IsUserResponsibleForOrganization(userId, tender.OrganizationID)
+	if !canManage {
+		s.logger.InfoContext(ctx, "Access denied", "user_id", userId, "tender_id", tenderId)
+		return models.Tender{}, my_errors.ErrForbidden
+	}
+
+	s.logger.DebugContext(ctx, "Fetching tender history", "tender_id", tenderId, "version", version)
+	history, err := s.repo.GetTenderHistoryByVersion(ctx, tenderId, version)
 	if err != nil {
-		log.Printf("RollbackTenderVersion: Error checking user responsibility: %v", err)
-		return err
+		if errors.Is(err, my_errors.ErrTenderHistoryNotFound) {
+			s.logger.InfoContext(ctx, "Tender history not found", "tender_id", tenderId, "version", version)
+			return models.Tender{}, my_errors.ErrTenderHistoryNotFound
+		}
+		s.logger.WarnContext(ctx, "Error fetching tender history", "error", err)
+		return models.Tender{}, err
 	}
 
-	if tender.CreatorID != userId && !isResponsible {
-		log.Printf("RollbackTenderVersion: Forbidden access for user: %s on tender ID: %s", username, tenderId)
-		return my_errors.ErrForbidden
+	if !tender.Status.IsEditable() {
+		s.logger.InfoContext(ctx, "Tender cannot be edited", "tender_id", tenderId, "status", tender.Status)
+		return models.Tender{}, my_errors.ErrTenderNotEditable
 	}
 
-	log.Printf("RollbackTenderVersion: Rolling back tender to version: %d", version)
+	if err := checkExpectedVersion(expectedVersion, tender.Version); err != nil {
+		s.logger.InfoContext(ctx, "Version conflict", "tender_id", tenderId, "expected_version", expectedVersion, "version", tender.Version)
+		return models.Tender{}, err
+	}
+
+	s.logger.DebugContext(ctx, "Rolling back tender", "tender_id", tenderId, "version", version)
 	tender.Name = history.Name
 	tender.Description = history.Description
 	tender.ServiceType = history.ServiceType
-	tender.Status = history.Status
+	tender.SubmissionDeadline = history.SubmissionDeadline
+	tender.DecisionDeadline = history.DecisionDeadline
 
-	log.Printf("RollbackTenderVersion: Updating tender with new values")
-	err = s.repo.UpdateTender(tender)
+	updated, err := inTransaction(ctx, s.uow, func(repos repository.Repositories) (models.Tender, error) {
+		updated, err := repos.Tenders.UpdateTender(ctx, tender)
+		if err != nil {
+			return models.Tender{}, err
+		}
+		return updated, repos.Outbox.Append(ctx, event.TenderRolledBack{Meta: event.NewMeta(userId), Tender: updated, Version: version})
+	})
 	if err != nil {
-		log.Printf("RollbackTenderVersion: Error updating tender: %v", err)
-		return err
+		s.logger.WarnContext(ctx, "Error updating tender", "error", err)
+		return models.Tender{}, err
 	}
 
-	log.Printf("RollbackTenderVersion: Successfully rolled back tender ID: %s to version: %d", tenderId, version)
-	return nil
+	s.logger.InfoContext(ctx, "Tender rolled back", "tender_id", tenderId, "to_version", version)
+	return updated, nil
 }
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	my_errors "tender-service/internal/errors"
+	"tender-service/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTenderStatus_Guards(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		from        models.TenderStatus
+		description string
+		bids        []models.BidStatus
+		to          models.TenderStatus
+		wantErr     error
+	}{
+		{"publish", models.Created, "Deliver equipment", nil, models.Published, nil},
+		{"publish without a description", models.Created, "", nil, models.Published, my_errors.ErrInvalidTenderTransition},
+		{"close before publishing", models.Created, "", nil, models.Closed, nil},
+		{"close with decided bids", models.Published, "Deliver equipment",
+			[]models.BidStatus{models.BidStatusCreated, models.BidStatusCanceled, models.BidStatusRejected}, models.Closed, nil},
+		{"close with a pending bid", models.Published, "Deliver equipment",
+			[]models.BidStatus{models.BidStatusRejected, models.BidStatusPublished}, models.Closed, my_errors.ErrInvalidTenderTransition},
+		{"unpublish", models.Published, "Deliver equipment", nil, models.Created, my_errors.ErrInvalidTenderTransition},
+		{"reopen", models.Closed, "Deliver equipment", nil, models.Published, my_errors.ErrInvalidTenderTransition},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t, 1)
+			tender := f.tender(t, tc.from, tc.description)
+			for _, status := range tc.bids {
+				f.bid(t, tender, status)
+			}
+
+			updated, err := f.tenderService.UpdateTenderStatus(f.as(f.responsibles[0]), tender.ID, tc.to, tender.Version)
+			stored, getErr := f.tenders.GetTenderByID(context.Background(), tender.ID)
+			require.NoError(t, getErr)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Equal(t, tc.from, stored.Status)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.to, updated.Status)
+			assert.Equal(t, tc.to, stored.Status)
+			assert.Equal(t, tender.Version+1, stored.Version)
+		})
+	}
+}
+
+func TestUpdateTenderStatus_Refusals(t *testing.T) {
+	f := newFixture(t, 1)
+	tender := f.tender(t, models.Created, "Deliver equipment")
+
+	_, err := f.tenderService.UpdateTenderStatus(f.as(f.bidder), tender.ID, models.Published, 0)
+	assert.ErrorIs(t, err, my_errors.ErrForbidden)
+	_, err = f.tenderService.UpdateTenderStatus(f.as(f.responsibles[0]), tender.ID, models.Published, tender.Version+1)
+	assert.ErrorIs(t, err, my_errors.ErrVersionConflict)
+	_, err = f.tenderService.UpdateTenderStatus(context.Background(), tender.ID, models.Published, 0)
+	assert.ErrorIs(t, err, my_errors.ErrUnauthorized)
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+	"tender-service/internal/repository"
+)
+
+// inTransaction runs fn in a unit of work and returns its result, or the zero
+// value when the unit rolls back.
+func inTransaction[T any](ctx context.Context, uow repository.UnitOfWork, fn func(repos repository.Repositories) (T, error)) (T, error) {
+	var result T
+	err := uow.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		var err error
+		result, err = fn(repos)
+		return err
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
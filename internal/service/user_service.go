@@ -1,27 +1,50 @@
 package service
 
 import (
-	my_errors "tender-service/internal/errors"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"tender-service/internal/auth"
 	"tender-service/internal/models"
 	"tender-service/internal/repository"
+
+	my_errors "tender-service/internal/errors"
+
+	"github.com/google/uuid"
 )
 
 type UserService interface {
-	GetUserIDByUsername(username string) (string, error)
-	GetUserByUsername(username string) (*models.User, error)
-	CheckUserPermission(userID, organizationID string) (bool, error)
+	GetUserIDByUsername(ctx context.Context, username string) (string, error)
+	GetUserByID(ctx context.Context, userID string) (*models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	CheckUserPermission(ctx context.Context, userID, organizationID string) (bool, error)
+
+	CreateEmployee(ctx context.Context, user models.User, password string) (models.User, error)
+	GetEmployee(ctx context.Context, userID string) (*models.User, error)
+	GetEmployeeByUsername(ctx context.Context, username string) (*models.User, error)
+	ListEmployees(ctx context.Context, filter repository.UserFilter) ([]models.User, error)
+	UpdateEmployee(ctx context.Context, userID string, firstName, lastName *string) (*models.User, error)
+	DeactivateEmployee(ctx context.Context, userID string) error
 }
 
 type userService struct {
-	repo repository.UserRepository
+	repo             repository.UserRepository
+	organizationRepo repository.OrganizationRepository
+	uow              repository.UnitOfWork
+	admins           adminSet
+	logger           *slog.Logger
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
-	return &userService{repo: repo}
+// NewUserService builds the service. Only the employees named in
+// adminUsernames, authenticated by a token, may create and deactivate
+// employees.
+func NewUserService(repo repository.UserRepository, organizationRepo repository.OrganizationRepository, uow repository.UnitOfWork, adminUsernames []string, logger *slog.Logger) UserService {
+	return &userService{repo: repo, organizationRepo: organizationRepo, uow: uow, admins: newAdminSet(adminUsernames), logger: logger}
 }
 
-func (s *userService) GetUserIDByUsername(username string) (string, error) {
-	userID, err := s.repo.FindUserIDByUsername(username)
+func (s *userService) GetUserIDByUsername(ctx context.Context, username string) (string, error) {
+	userID, err := s.repo.FindUserIDByUsername(ctx, username)
 	if err != nil {
 		if err == my_errors.ErrUserNotFound {
 			return "", my_errors.ErrUserNotFound
@@ -31,8 +54,12 @@ func (s *userService) GetUserIDByUsername(username string) (string, error) {
 	return userID, nil
 }
 
-func (s *userService) GetUserByUsername(username string) (*models.User, error) {
-	user, err := s.repo.GetUserByUsername(username)
+func (s *userService) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+func (s *userService) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
 	if err != nil {
 		if err == my_errors.ErrUserNotFound {
 			return nil, my_errors.ErrUserNotFound
@@ -42,8 +69,8 @@ func (s *userService) GetUserByUsername(username string) (*models.User, error) {
 	return user, nil
 }
 
-func (s *userService) CheckUserPermission(userID, organizationID string) (bool, error) {
-	hasPermission, err := s.repo.CheckUserPermission(userID, organizationID)
+func (s *userService) CheckUserPermission(ctx context.Context, userID, organizationID string) (bool, error) {
+	hasPermission, err := s.repo.CheckUserPermission(ctx, userID, organizationID)
 	if err != nil {
 		return false, err
 	}
@@ -52,3 +79,163 @@ func (s *userService) CheckUserPermission(userID, organizationID string) (bool,
 	}
 	return true, nil
 }
+
+// caller returns the employee making the request. Every employee management
+// endpoint requires one.
+func (s *userService) caller(ctx context.Context) (*models.User, error) {
+	user, err := auth.Caller(ctx, s.repo)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrUserNotFound) {
+			return nil, my_errors.ErrUnauthorized
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// CreateEmployee registers a new employee on behalf of an admin. The password
+// is optional; without it the employee cannot obtain tokens until one is set.
+func (s *userService) CreateEmployee(ctx context.Context, user models.User, password string) (models.User, error) {
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return models.User{}, err
+	}
+	if !s.admins.allows(ctx, caller) {
+		s.logger.InfoContext(ctx, "Employee creation denied", "actor", caller.Username)
+		return models.User{}, my_errors.ErrForbidden
+	}
+
+	user.Username = strings.TrimSpace(user.Username)
+	if user.Username == "" || len(user.Username) > 50 || len(user.FirstName) > 50 || len(user.LastName) > 50 {
+		return models.User{}, my_errors.ErrBadRequest
+	}
+
+	var hash string
+	if password != "" {
+		if hash, err = auth.HashPassword(password); err != nil {
+			return models.User{}, err
+		}
+	}
+
+	// The employee and the password are stored together, so a failure cannot
+	// leave an employee that was asked for a password without one.
+	created, err := inTransaction(ctx, s.uow, func(repos repository.Repositories) (models.User, error) {
+		created, err := repos.Users.CreateUser(ctx, user)
+		if err != nil {
+			return models.User{}, err
+		}
+		if hash != "" {
+			return created, repos.Users.SetPasswordHash(ctx, created.ID, hash)
+		}
+		return created, nil
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	s.logger.InfoContext(ctx, "Employee created", "username", created.Username, "actor", caller.Username)
+	return created, nil
+}
+
+func (s *userService) GetEmployee(ctx context.Context, userID string) (*models.User, error) {
+	if _, err := s.caller(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, my_errors.ErrBadRequest
+	}
+	return s.repo.GetUserByID(ctx, userID)
+}
+
+func (s *userService) GetEmployeeByUsername(ctx context.Context, username string) (*models.User, error) {
+	if _, err := s.caller(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByUsername(ctx, username)
+}
+
+func (s *userService) ListEmployees(ctx context.Context, filter repository.UserFilter) ([]models.User, error) {
+	if _, err := s.caller(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.ListUsers(ctx, filter)
+}
+
+func (s *userService) UpdateEmployee(ctx context.Context, userID string, firstName, lastName *string) (*models.User, error) {
+	caller, err := s.authorizeEmployeeChange(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if (firstName != nil && len(*firstName) > 50) || (lastName != nil && len(*lastName) > 50) {
+		return nil, my_errors.ErrBadRequest
+	}
+
+	user, err := s.repo.UpdateUserNames(ctx, userID, firstName, lastName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.InfoContext(ctx, "Employee updated", "username", user.Username, "actor", caller.Username)
+	return user, nil
+}
+
+// DeactivateEmployee stops the employee from being found by ID or username,
+// so they can no longer authenticate or take part in tenders and bids. Only
+// admins may do it: the approval quorum counts active responsibles only, so
+// a responsible deactivating the others could approve bids alone.
+func (s *userService) DeactivateEmployee(ctx context.Context, userID string) error {
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return err
+	}
+	if !s.admins.allows(ctx, caller) {
+		s.logger.InfoContext(ctx, "Employee deactivation denied", "user_id", userID, "actor", caller.Username)
+		return my_errors.ErrForbidden
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return my_errors.ErrBadRequest
+	}
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeactivateUser(ctx, userID); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Employee deactivated", "user_id", userID, "actor", caller.Username)
+	return nil
+}
+
+// authorizeEmployeeChange lets employees change themselves and responsibles
+// change the employees they share an organization with.
+func (s *userService) authorizeEmployeeChange(ctx context.Context, userID string) (*models.User, error) {
+	caller, err := s.caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, my_errors.ErrBadRequest
+	}
+	if _, err := s.repo.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if caller.ID == userID {
+		return caller, nil
+	}
+
+	organizations, err := s.organizationRepo.GetUserOrganizations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, organization := range organizations {
+		responsible, err := s.organizationRepo.IsResponsible(ctx, caller.ID, organization.ID)
+		if err != nil {
+			return nil, err
+		}
+		if responsible {
+			return caller, nil
+		}
+	}
+	return nil, my_errors.ErrForbidden
+}
@@ -0,0 +1,12 @@
+package service
+
+import my_errors "tender-service/internal/errors"
+
+// checkExpectedVersion rejects a change made against a stale copy of an
+// entity. An expected version of zero means the client did not send one.
+func checkExpectedVersion(expected, current int) error {
+	if expected != 0 && expected != current {
+		return my_errors.ErrVersionConflict
+	}
+	return nil
+}
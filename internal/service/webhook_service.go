@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/url"
+	"tender-service/internal/auth"
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+	"tender-service/internal/webhook"
+
+	my_errors "tender-service/internal/errors"
+
+	"github.com/google/uuid"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, organizationID, webhookURL, secret string, eventTypes []string) (models.WebhookSubscription, error)
+	GetWebhooks(ctx context.Context, organizationID string) ([]models.WebhookSubscription, error)
+	GetWebhook(ctx context.Context, organizationID, webhookID string) (models.WebhookSubscription, error)
+	UpdateWebhook(ctx context.Context, organizationID, webhookID string, webhookURL, secret *string, eventTypes *[]string, active *bool) (models.WebhookSubscription, error)
+	DeleteWebhook(ctx context.Context, organizationID, webhookID string) error
+	GetWebhookDeliveries(ctx context.Context, organizationID, webhookID string, limit, offset int) ([]models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repo             repository.WebhookRepository
+	organizationRepo repository.OrganizationRepository
+	userRepo         repository.UserRepository
+	logger           *slog.Logger
+}
+
+func NewWebhookService(repo repository.WebhookRepository, organizationRepo repository.OrganizationRepository, userRepo repository.UserRepository, logger *slog.Logger) WebhookService {
+	return &webhookService{repo: repo, organizationRepo: organizationRepo, userRepo: userRepo, logger: logger}
+}
+
+// authorize lets the responsibles of the organization manage its webhooks.
+func (s *webhookService) authorize(ctx context.Context, organizationID string) (*models.User, error) {
+	user, err := auth.Caller(ctx, s.userRepo)
+	if err != nil {
+		if errors.Is(err, my_errors.ErrUserNotFound) {
+			return nil, my_errors.ErrUnauthorized
+		}
+		return nil, err
+	}
+	if _, err := uuid.Parse(organizationID); err != nil {
+		return nil, my_errors.ErrBadRequest
+	}
+	if _, err := s.organizationRepo.GetOrganizationByID(ctx, organizationID); err != nil {
+		return nil, err
+	}
+
+	responsible, err := s.organizationRepo.IsResponsible(ctx, user.ID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !responsible {
+		return nil, my_errors.ErrForbidden
+	}
+	return user, nil
+}
+
+// webhook returns the organization's subscription, hiding those of other
+// organizations as missing.
+func (s *webhookService) webhook(ctx context.Context, organizationID, webhookID string) (models.WebhookSubscription, error) {
+	if _, err := uuid.Parse(webhookID); err != nil {
+		return models.WebhookSubscription{}, my_errors.ErrBadRequest
+	}
+	subscription, err := s.repo.GetSubscription(ctx, webhookID)
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	if subscription.OrganizationID != organizationID {
+		return models.WebhookSubscription{}, my_errors.ErrWebhookNotFound
+	}
+	return subscription, nil
+}
+
+// CreateWebhook subscribes the organization. Without a secret one is
+// generated; either way it is only returned here.
+func (s *webhookService) CreateWebhook(ctx context.Context, organizationID, webhookURL, secret string, eventTypes []string) (models.WebhookSubscription, error) {
+	user, err := s.authorize(ctx, organizationID)
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	if err := validateWebhook(webhookURL, secret, eventTypes); err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return models.WebhookSubscription{}, err
+		}
+	}
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	created, err := s.repo.CreateSubscription(ctx, models.WebhookSubscription{
+		OrganizationID: organizationID,
+		URL:            webhookURL,
+		Secret:         secret,
+		EventTypes:     eventTypes,
+	})
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	s.logger.InfoContext(ctx, "Webhook created", "webhook_id", created.ID, "organization_id", organizationID, "actor", user.Username)
+	return created, nil
+}
+
+func (s *webhookService) GetWebhooks(ctx context.Context, organizationID string) ([]models.WebhookSubscription, error) {
+	if _, err := s.authorize(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	subscriptions, err := s.repo.ListSubscriptions(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, organizationID, webhookID string) (models.WebhookSubscription, error) {
+	if _, err := s.authorize(ctx, organizationID); err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	subscription, err := s.webhook(ctx, organizationID, webhookID)
+	subscription.Secret = ""
+	return subscription, err
+}
+
+// UpdateWebhook changes the given fields. Turning a subscription on resets
+// its failure count, so a disabled one gets a fresh start.
+func (s *webhookService) UpdateWebhook(ctx context.Context, organizationID, webhookID string, webhookURL, secret *string, eventTypes *[]string, active *bool) (models.WebhookSubscription, error) {
+	user, err := s.authorize(ctx, organizationID)
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	subscription, err := s.webhook(ctx, organizationID, webhookID)
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	if webhookURL != nil {
+		subscription.URL = *webhookURL
+	}
+	if secret != nil {
+		if *secret == "" {
+			return models.WebhookSubscription{}, my_errors.ErrBadRequest
+		}
+		subscription.Secret = *secret
+	}
+	if eventTypes != nil {
+		subscription.EventTypes = *eventTypes
+		if subscription.EventTypes == nil {
+			subscription.EventTypes = []string{}
+		}
+	}
+	if err := validateWebhook(subscription.URL, subscription.Secret, subscription.EventTypes); err != nil {
+		return models.WebhookSubscription{}, err
+	}
+	if active != nil && *active != subscription.Active {
+		subscription.Active = *active
+		if subscription.Active {
+			subscription.ConsecutiveFailures = 0
+			subscription.DisabledAt = nil
+		}
+	}
+
+	updated, err := s.repo.UpdateSubscription(ctx, subscription)
+	if err != nil {
+		return models.WebhookSubscription{}, err
+	}
+
+	s.logger.InfoContext(ctx, "Webhook updated", "webhook_id", webhookID, "organization_id", organizationID, "actor", user.Username)
+	updated.Secret = ""
+	return updated, nil
+}
+
+func (s *webhookService) DeleteWebhook(ctx context.Context, organizationID, webhookID string) error {
+	user, err := s.authorize(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.webhook(ctx, organizationID, webhookID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSubscription(ctx, webhookID); err != nil {
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Webhook deleted", "webhook_id", webhookID, "organization_id", organizationID, "actor", user.Username)
+	return nil
+}
+
+// GetWebhookDeliveries lists the subscription's deliveries, newest first.
+func (s *webhookService) GetWebhookDeliveries(ctx context.Context, organizationID, webhookID string, limit, offset int) ([]models.WebhookDelivery, error) {
+	if _, err := s.authorize(ctx, organizationID); err != nil {
+		return nil, err
+	}
+	if _, err := s.webhook(ctx, organizationID, webhookID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, webhookID, limit, offset)
+}
+
+// validateWebhook accepts absolute http and https URLs to public hosts,
+// secrets of up to 200 bytes and known event types.
+func validateWebhook(rawURL, secret string, eventTypes []string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || len(rawURL) > 2048 {
+		return my_errors.ErrBadRequest
+	}
+	if !webhook.PublicHost(parsed.Hostname()) {
+		return my_errors.ErrBadRequest
+	}
+	if len(secret) > 200 {
+		return my_errors.ErrBadRequest
+	}
+	for _, eventType := range eventTypes {
+		if !event.Type(eventType).Known() {
+			return my_errors.ErrBadRequest
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
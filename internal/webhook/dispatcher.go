@@ -0,0 +1,171 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"tender-service/internal/models"
+	"tender-service/internal/outbox"
+	"tender-service/internal/repository"
+)
+
+// Headers of every webhook request.
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+	// timestamp, a dot and the body, keyed with the subscription's secret.
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader carries the Unix time the request was signed at, so
+	// receivers can reject replayed requests.
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	batchSize = 50
+	// lease hides a claimed delivery from other dispatchers; it exceeds the
+	// time a batch can take with every request timing out.
+	lease = 10 * time.Minute
+	// maxAttempts is how many attempts a delivery gets before it is failed.
+	maxAttempts = 8
+	// disableAfter is how many attempts in a row may fail, across all of a
+	// subscription's deliveries, before the subscription is disabled.
+	disableAfter = 20
+
+	requestTimeout = 10 * time.Second
+	// maxErrorBody bounds how much of an error response is kept in the log.
+	maxErrorBody = 512
+)
+
+// Sign returns the SignatureHeader value for a body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher POSTs pending deliveries to their subscriptions. A 2xx response
+// delivers; anything else is retried with outbox.Backoff until maxAttempts.
+// Like the outbox relay, several dispatchers may share the deliveries.
+type Dispatcher struct {
+	repo     repository.WebhookRepository
+	client   *http.Client
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewDispatcher builds a dispatcher sending with client. When client is nil it
+// uses one that times out after requestTimeout and refuses non-public
+// addresses (see PublicIP).
+func NewDispatcher(repo repository.WebhookRepository, client *http.Client, interval time.Duration, logger *slog.Logger) *Dispatcher {
+	if client == nil {
+		client = newClient()
+	}
+	return &Dispatcher{repo: repo, client: client, interval: interval, logger: logger}
+}
+
+// Run sends due deliveries every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx, time.Now()); err != nil {
+			d.logger.ErrorContext(ctx, "Error delivering webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue sends the deliveries due at now, batch by batch, and returns how
+// many succeeded.
+func (d *Dispatcher) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	delivered := 0
+	for {
+		deliveries, err := d.repo.ClaimDueDeliveries(ctx, now, lease, batchSize)
+		if err != nil {
+			return delivered, err
+		}
+		for _, delivery := range deliveries {
+			ok, err := d.deliver(ctx, delivery, now)
+			if err != nil {
+				return delivered, err
+			}
+			if ok {
+				delivered++
+			}
+		}
+		if len(deliveries) < batchSize {
+			return delivered, nil
+		}
+	}
+}
+
+// deliver sends the delivery and records the outcome. It reports whether the
+// delivery succeeded; the error is about recording the outcome.
+func (d *Dispatcher) deliver(ctx context.Context, delivery models.WebhookDelivery, now time.Time) (bool, error) {
+	subscription, err := d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		return false, err
+	}
+
+	status, sendErr := d.send(ctx, subscription, delivery)
+	if sendErr == nil {
+		return true, d.repo.MarkDeliverySucceeded(ctx, delivery.ID, status, now)
+	}
+
+	final := delivery.Attempts >= maxAttempts
+	d.logger.WarnContext(ctx, "Error delivering webhook", "event_type", delivery.EventType, "webhook_id", subscription.ID, "attempt", delivery.Attempts, "error", sendErr)
+	disabled, err := d.repo.MarkDeliveryFailed(ctx, delivery.ID, status, sendErr.Error(), now.Add(outbox.Backoff(delivery.Attempts)), final, disableAfter)
+	if err != nil {
+		return false, fmt.Errorf("recording failure of webhook delivery %s: %w", delivery.ID, err)
+	}
+	if disabled && subscription.Active {
+		d.logger.WarnContext(ctx, "Webhook disabled after failed attempts in a row", "webhook_id", subscription.ID, "organization_id", subscription.OrganizationID, "attempts", disableAfter)
+	}
+	return false, nil
+}
+
+// send POSTs the delivery and returns the response status, zero when there
+// was no response.
+func (d *Dispatcher) send(ctx context.Context, subscription models.WebhookSubscription, delivery models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("unexpected response %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,239 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"tender-service/internal/event"
+	"tender-service/internal/logging"
+	"tender-service/internal/models"
+	"tender-service/internal/outbox"
+	"tender-service/internal/repository"
+	"tender-service/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tenderOrganizationID = "550e8400-e29b-41d4-a716-446655440020"
+	bidderOrganizationID = "550e8400-e29b-41d4-a716-446655440022"
+)
+
+type fixture struct {
+	webhooks repository.WebhookRepository
+	outbox   repository.OutboxRepository
+	fanout   *Fanout
+	relay    *outbox.Relay
+	tender   models.Tender
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	tenders := memory.NewTenderRepository(store)
+	tender, err := tenders.CreateTender(context.Background(), models.Tender{Name: "Delivery", Status: models.Published, OrganizationID: tenderOrganizationID})
+	require.NoError(t, err)
+
+	f := fixture{
+		webhooks: memory.NewWebhookRepository(store),
+		outbox:   memory.NewOutboxRepository(store),
+		tender:   tender,
+	}
+	f.relay = outbox.NewRelay(f.outbox, time.Second, logging.Discard())
+	f.fanout = NewFanout(f.webhooks, tenders)
+	f.relay.Register(f.fanout)
+	return f
+}
+
+func (f fixture) subscribe(t *testing.T, organizationID, url string, eventTypes ...string) models.WebhookSubscription {
+	t.Helper()
+	subscription, err := f.webhooks.CreateSubscription(context.Background(), models.WebhookSubscription{
+		OrganizationID: organizationID,
+		URL:            url,
+		Secret:         "secret-" + organizationID,
+		EventTypes:     eventTypes,
+	})
+	require.NoError(t, err)
+	return subscription
+}
+
+// publish stores the events in the outbox and fans them out.
+func (f fixture) publish(t *testing.T, events ...event.Event) {
+	t.Helper()
+	require.NoError(t, f.outbox.Append(context.Background(), events...))
+	_, err := f.relay.DeliverDue(context.Background(), time.Now())
+	require.NoError(t, err)
+}
+
+func (f fixture) bid(status models.BidStatus) models.Bid {
+	return models.Bid{ID: "550e8400-e29b-41d4-a716-446655440100", TenderID: f.tender.ID, OrganizationID: bidderOrganizationID, Status: status}
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver records the requests it gets and answers them with status.
+func receiver(t *testing.T, status int) (*httptest.Server, func() []receivedRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var received []receivedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		received = append(received, receivedRequest{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]receivedRequest{}, received...)
+	}
+}
+
+func TestDispatcher_DeliversSignedEventsToInterestedOrganizations(t *testing.T) {
+	f := newFixture(t)
+	tenderServer, tenderReceived := receiver(t, http.StatusNoContent)
+	bidderServer, bidderReceived := receiver(t, http.StatusOK)
+	tenderSubscription := f.subscribe(t, tenderOrganizationID, tenderServer.URL)
+	bidderSubscription := f.subscribe(t, bidderOrganizationID, bidderServer.URL, string(event.TypeBidStatusChanged))
+
+	meta := event.NewMeta("")
+	f.publish(t,
+		// A created bid is not visible to the tender's organization yet, and
+		// the bidder only asked for status changes.
+		event.BidCreated{Meta: meta, Bid: f.bid(models.BidStatusCreated)},
+		event.BidStatusChanged{Meta: meta, Bid: f.bid(models.BidStatusPublished), PreviousStatus: models.BidStatusCreated},
+		event.BidDecisionSubmitted{Meta: meta, Bid: f.bid(models.BidStatusPublished), Decision: models.BidDecisionApproved},
+	)
+	// Fanning out the same entries again must not deliver them twice.
+	entries, err := f.outbox.ListOutbox(context.Background(), nil, 10, 0)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		require.NoError(t, f.fanout.Deliver(context.Background(), entry))
+	}
+
+	dispatcher := NewDispatcher(f.webhooks, &http.Client{}, time.Second, logging.Discard())
+	delivered, err := dispatcher.DeliverDue(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 3, delivered)
+
+	var types []string
+	for _, request := range tenderReceived() {
+		timestamp, err := strconv.ParseInt(request.header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, Sign(tenderSubscription.Secret, timestamp, request.body), request.header.Get(SignatureHeader))
+		assert.NotEqual(t, Sign(bidderSubscription.Secret, timestamp, request.body), request.header.Get(SignatureHeader))
+
+		var payload Payload
+		require.NoError(t, json.Unmarshal(request.body, &payload))
+		assert.Equal(t, request.header.Get(EventHeader), payload.Type)
+		assert.NotZero(t, payload.ID)
+		types = append(types, payload.Type)
+	}
+	assert.Equal(t, []string{string(event.TypeBidStatusChanged), string(event.TypeBidDecisionSubmitted)}, types)
+
+	require.Len(t, bidderReceived(), 1)
+	assert.Equal(t, string(event.TypeBidStatusChanged), bidderReceived()[0].header.Get(EventHeader))
+
+	deliveries, err := f.webhooks.ListDeliveries(context.Background(), tenderSubscription.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		assert.Equal(t, models.WebhookDeliveryDelivered, delivery.Status)
+		assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.NotNil(t, delivery.DeliveredAt)
+	}
+}
+
+func TestDispatcher_RetriesAndDisablesFailingSubscription(t *testing.T) {
+	f := newFixture(t)
+	server, received := receiver(t, http.StatusServiceUnavailable)
+	subscription := f.subscribe(t, tenderOrganizationID, server.URL)
+
+	meta := event.NewMeta("")
+	f.publish(t,
+		event.TenderPublished{Meta: meta, Tender: f.tender},
+		event.TenderEdited{Meta: meta, Tender: f.tender},
+		event.TenderClosed{Meta: meta, Tender: f.tender},
+	)
+
+	dispatcher := NewDispatcher(f.webhooks, &http.Client{}, time.Second, logging.Discard())
+	now := time.Now()
+	for round := 0; round < maxAttempts; round++ {
+		delivered, err := dispatcher.DeliverDue(context.Background(), now)
+		require.NoError(t, err)
+		assert.Zero(t, delivered)
+		// Retries wait for their backoff.
+		delivered, err = dispatcher.DeliverDue(context.Background(), now)
+		require.NoError(t, err)
+		assert.Zero(t, delivered)
+		now = now.Add(lease)
+	}
+
+	// Three deliveries fail together in every round, so the subscription is
+	// disabled in the round that brings the failures to disableAfter.
+	assert.Len(t, received(), (disableAfter+2)/3*3)
+
+	stored, err := f.webhooks.GetSubscription(context.Background(), subscription.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Active)
+	assert.NotNil(t, stored.DisabledAt)
+	assert.GreaterOrEqual(t, stored.ConsecutiveFailures, disableAfter)
+
+	deliveries, err := f.webhooks.ListDeliveries(context.Background(), subscription.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 3)
+	for _, delivery := range deliveries {
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseStatus)
+		assert.Contains(t, delivery.LastError, "503")
+	}
+}
+
+func TestDispatcher_DefaultClientRefusesNonPublicAddresses(t *testing.T) {
+	f := newFixture(t)
+	server, received := receiver(t, http.StatusNoContent)
+	subscription := f.subscribe(t, tenderOrganizationID, server.URL)
+	f.publish(t, event.TenderPublished{Meta: event.NewMeta(""), Tender: f.tender})
+
+	dispatcher := NewDispatcher(f.webhooks, nil, time.Second, logging.Discard())
+	delivered, err := dispatcher.DeliverDue(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, delivered)
+	assert.Empty(t, received())
+
+	deliveries, err := f.webhooks.ListDeliveries(context.Background(), subscription.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Contains(t, deliveries[0].LastError, "not public")
+
+	for host, public := range map[string]bool{
+		"example.com":     true,
+		"93.184.216.34":   true,
+		"localhost":       false,
+		"api.localhost.":  false,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"169.254.169.254": false,
+		"::1":             false,
+		"fd00::1":         false,
+		"0.0.0.0":         false,
+	} {
+		assert.Equal(t, public, PublicHost(host), host)
+	}
+}
@@ -0,0 +1,115 @@
+// Package webhook delivers events to the URLs organizations subscribe with.
+//
+// Delivery takes two steps. Fanout, an outbox sink, stores a delivery for
+// every subscription that wants an event. Dispatcher then POSTs the stored
+// deliveries, retrying failed ones with a growing delay.
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"tender-service/internal/event"
+	"tender-service/internal/models"
+	"tender-service/internal/repository"
+)
+
+// Payload is the body POSTed to subscribers. ID identifies the event across
+// retries, so receivers can drop duplicates.
+type Payload struct {
+	ID   int64           `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Fanout stores a delivery of each event for every active subscription of
+// the organizations the event concerns. It is idempotent, so the outbox may
+// hand it the same event again.
+type Fanout struct {
+	repo    repository.WebhookRepository
+	tenders repository.TenderRepository
+}
+
+func NewFanout(repo repository.WebhookRepository, tenders repository.TenderRepository) *Fanout {
+	return &Fanout{repo: repo, tenders: tenders}
+}
+
+func (f *Fanout) Deliver(ctx context.Context, entry models.OutboxEntry) error {
+	e, err := event.Decode(event.Type(entry.EventType), entry.Payload)
+	if err != nil {
+		return err
+	}
+	organizationIDs, err := f.organizations(ctx, e)
+	if err != nil || len(organizationIDs) == 0 {
+		return err
+	}
+
+	subscriptions, err := f.repo.MatchSubscriptions(ctx, organizationIDs, entry.EventType)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(Payload{ID: entry.ID, Type: entry.EventType, Data: entry.Payload})
+	if err != nil {
+		return err
+	}
+	deliveries := make([]models.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        entry.ID,
+			EventType:      entry.EventType,
+			Payload:        payload,
+		})
+	}
+	return f.repo.EnqueueDeliveries(ctx, deliveries...)
+}
+
+// organizations returns the organizations that may learn of the event. Tender
+// events concern the tender's organization. Bid events concern the bidding
+// organization and, once the bid is visible to it, the tender's organization;
+// single decisions stay with the tender's organization until they decide the
+// bid.
+func (f *Fanout) organizations(ctx context.Context, e event.Event) ([]string, error) {
+	switch e := e.(type) {
+	case event.TenderCreated:
+		return []string{e.Tender.OrganizationID}, nil
+	case event.TenderPublished:
+		return []string{e.Tender.OrganizationID}, nil
+	case event.TenderClosed:
+		return []string{e.Tender.OrganizationID}, nil
+	case event.TenderEdited:
+		return []string{e.Tender.OrganizationID}, nil
+	case event.TenderRolledBack:
+		return []string{e.Tender.OrganizationID}, nil
+	case event.BidDecisionSubmitted:
+		return f.bidOrganizations(ctx, e.Bid, false)
+	case event.BidCreated:
+		return f.bidOrganizations(ctx, e.Bid, true)
+	case event.BidStatusChanged:
+		return f.bidOrganizations(ctx, e.Bid, true)
+	case event.FeedbackAdded:
+		return f.bidOrganizations(ctx, e.Bid, true)
+	default:
+		return nil, nil
+	}
+}
+
+func (f *Fanout) bidOrganizations(ctx context.Context, bid models.Bid, includeBidder bool) ([]string, error) {
+	var organizationIDs []string
+	if includeBidder {
+		organizationIDs = append(organizationIDs, bid.OrganizationID)
+	}
+	if !bid.Status.IsVisibleToTender() {
+		return organizationIDs, nil
+	}
+
+	tender, err := f.tenders.GetTenderByID(ctx, bid.TenderID)
+	if err != nil {
+		return nil, err
+	}
+	if tender.OrganizationID != bid.OrganizationID || !includeBidder {
+		organizationIDs = append(organizationIDs, tender.OrganizationID)
+	}
+	return organizationIDs, nil
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS organization_responsible;
+DROP TABLE IF EXISTS organization;
+DROP TYPE IF EXISTS organization_type;
+DROP TABLE IF EXISTS employee;
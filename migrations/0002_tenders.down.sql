@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS tender_update_trigger ON tender;
+DROP FUNCTION IF EXISTS save_tender_version();
+DROP TABLE IF EXISTS tender_history;
+DROP TABLE IF EXISTS tender;
+DROP TYPE IF EXISTS tender_status;
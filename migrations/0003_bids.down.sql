@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS bid_review;
+DROP TABLE IF EXISTS bid;
+DROP TYPE IF EXISTS bid_status;
+DROP TYPE IF EXISTS bid_author_type;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS bid_update_trigger ON bid;
+DROP FUNCTION IF EXISTS save_bid_version();
+DROP TABLE IF EXISTS bid_history;
+ALTER TABLE bid DROP COLUMN IF EXISTS version;
@@ -0,0 +1,40 @@
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS version INT DEFAULT 1;
+
+
+CREATE TABLE IF NOT EXISTS bid_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+    tender_id UUID,
+    organization_id UUID,
+    user_id UUID,
+    author_type bid_author_type,
+    description TEXT,
+    status bid_status,
+    version INT,
+    updated_at TIMESTAMP
+);
+
+
+CREATE OR REPLACE FUNCTION save_bid_version()
+RETURNS TRIGGER AS $$
+BEGIN
+
+    INSERT INTO bid_history (bid_id, tender_id, organization_id, user_id, author_type, description, status, version, updated_at)
+    SELECT OLD.id, OLD.tender_id, OLD.organization_id, OLD.user_id, OLD.author_type, OLD.description, OLD.status, OLD.version, OLD.updated_at;
+
+
+    IF TG_OP = 'UPDATE' THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+DROP TRIGGER IF EXISTS bid_update_trigger ON bid;
+
+CREATE TRIGGER bid_update_trigger
+BEFORE UPDATE ON bid
+FOR EACH ROW
+EXECUTE FUNCTION save_bid_version();
@@ -0,0 +1,3 @@
+-- PostgreSQL cannot drop enum values, so APPROVED and REJECTED stay in bid_status.
+DROP TABLE IF EXISTS bid_decision;
+DROP TYPE IF EXISTS bid_decision;
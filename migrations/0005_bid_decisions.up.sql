@@ -0,0 +1,23 @@
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'APPROVED';
+ALTER TYPE bid_status ADD VALUE IF NOT EXISTS 'REJECTED';
+
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'bid_decision') THEN
+        CREATE TYPE bid_decision AS ENUM (
+            'Approved',
+            'Rejected'
+        );
+    END IF;
+END $$;
+
+
+CREATE TABLE IF NOT EXISTS bid_decision (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bid_id UUID REFERENCES bid(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES employee(id) ON DELETE CASCADE,
+    decision bid_decision NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (bid_id, user_id)
+);
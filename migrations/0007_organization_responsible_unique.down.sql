@@ -0,0 +1 @@
+DROP INDEX IF EXISTS organization_responsible_organization_user_idx;
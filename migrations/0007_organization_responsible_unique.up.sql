@@ -0,0 +1,11 @@
+-- Responsibles used to be seeded by hand, so drop accidental duplicates
+-- before making membership unique.
+DELETE FROM organization_responsible a
+USING organization_responsible b
+WHERE a.organization_id = b.organization_id
+  AND a.user_id = b.user_id
+  AND a.id > b.id;
+
+
+CREATE UNIQUE INDEX IF NOT EXISTS organization_responsible_organization_user_idx
+    ON organization_responsible (organization_id, user_id);
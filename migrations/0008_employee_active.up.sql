@@ -0,0 +1 @@
+ALTER TABLE employee ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
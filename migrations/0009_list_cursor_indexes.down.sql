@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS tender_created_idx;
+DROP INDEX IF EXISTS bid_user_created_idx;
+DROP INDEX IF EXISTS bid_tender_created_idx;
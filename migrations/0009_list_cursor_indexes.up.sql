@@ -0,0 +1,5 @@
+-- Keyset pages walk (created_at, id) within a tender, an author or the
+-- whole tender list.
+CREATE INDEX IF NOT EXISTS bid_tender_created_idx ON bid (tender_id, created_at, id);
+CREATE INDEX IF NOT EXISTS bid_user_created_idx ON bid (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS tender_created_idx ON tender (created_at, id);
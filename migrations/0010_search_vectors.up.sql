@@ -0,0 +1,15 @@
+-- The 'simple' configuration neither stems nor drops stop words, so Russian
+-- and English texts are matched the same way, word by word.
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS tender_search_idx ON tender USING GIN (search_vector);
+
+
+ALTER TABLE bid ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(description, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS bid_search_idx ON bid USING GIN (search_vector);
@@ -0,0 +1,26 @@
+DROP INDEX IF EXISTS tender_expiry_idx;
+
+
+CREATE OR REPLACE FUNCTION save_tender_version()
+RETURNS TRIGGER AS $$
+BEGIN
+
+    INSERT INTO tender_history (tender_id, name, description, service_type, status, organization_id, creator_id, version, updated_at)
+    SELECT OLD.id, OLD.name, OLD.description, OLD.service_type, OLD.status, OLD.organization_id, OLD.creator_id, OLD.version, OLD.updated_at;
+
+
+    IF TG_OP = 'UPDATE' THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+ALTER TABLE tender_history DROP COLUMN IF EXISTS decision_deadline;
+ALTER TABLE tender_history DROP COLUMN IF EXISTS submission_deadline;
+
+ALTER TABLE tender DROP CONSTRAINT IF EXISTS tender_deadlines_order;
+ALTER TABLE tender DROP COLUMN IF EXISTS decision_deadline;
+ALTER TABLE tender DROP COLUMN IF EXISTS submission_deadline;
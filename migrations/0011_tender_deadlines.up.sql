@@ -0,0 +1,32 @@
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMPTZ;
+ALTER TABLE tender ADD COLUMN IF NOT EXISTS decision_deadline TIMESTAMPTZ;
+
+ALTER TABLE tender DROP CONSTRAINT IF EXISTS tender_deadlines_order;
+ALTER TABLE tender ADD CONSTRAINT tender_deadlines_order
+    CHECK (decision_deadline >= submission_deadline);
+
+ALTER TABLE tender_history ADD COLUMN IF NOT EXISTS submission_deadline TIMESTAMPTZ;
+ALTER TABLE tender_history ADD COLUMN IF NOT EXISTS decision_deadline TIMESTAMPTZ;
+
+
+CREATE OR REPLACE FUNCTION save_tender_version()
+RETURNS TRIGGER AS $$
+BEGIN
+
+    INSERT INTO tender_history (tender_id, name, description, service_type, status, organization_id, creator_id, version, updated_at, submission_deadline, decision_deadline)
+    SELECT OLD.id, OLD.name, OLD.description, OLD.service_type, OLD.status, OLD.organization_id, OLD.creator_id, OLD.version, OLD.updated_at, OLD.submission_deadline, OLD.decision_deadline;
+
+
+    IF TG_OP = 'UPDATE' THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+
+-- Lets the deadline scheduler find expired published tenders without a scan.
+CREATE INDEX IF NOT EXISTS tender_expiry_idx
+    ON tender ((COALESCE(decision_deadline, submission_deadline)))
+    WHERE status = 'PUBLISHED';
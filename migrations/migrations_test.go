@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_EmbeddedMigrationsAreOrderedAndReversible(t *testing.T) {
+	migrations, err := Load()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migration versions must be contiguous")
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down, "migration %d_%s has no down script", migration.Version, migration.Name)
+	}
+}
+
+func TestLoad_MissingUpScript(t *testing.T) {
+	_, err := load(fstest.MapFS{
+		"0001_init.down.sql": {Data: []byte("DROP TABLE t;")},
+	})
+	assert.Error(t, err)
+}
+
+func TestLoad_ConflictingNames(t *testing.T) {
+	_, err := load(fstest.MapFS{
+		"0001_init.up.sql":  {Data: []byte("CREATE TABLE t ();")},
+		"0001_other.up.sql": {Data: []byte("CREATE TABLE u ();")},
+	})
+	assert.Error(t, err)
+}
+
+func TestLoad_IgnoresOtherFiles(t *testing.T) {
+	migrations, err := load(fstest.MapFS{
+		"0002_second.up.sql": {Data: []byte("SELECT 2;")},
+		"0001_first.up.sql":  {Data: []byte("SELECT 1;")},
+		"README.md":          {Data: []byte("notes")},
+	})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, "first", migrations[0].Name)
+	assert.Equal(t, "second", migrations[1].Name)
+}